@@ -1,11 +1,7 @@
 package compact
 
 import (
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
-
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -24,50 +20,25 @@ func Cmd() *cobra.Command {
 	cmd.RunE = func(cmd *cobra.Command, _ []string) error {
 		ctx := cmd.Context()
 		logger := utils.LoggerFromContext(ctx)
-		client := utils.HttpClientFromContext(ctx)
-
-		rootFlags := utils.RootCommandFlags(cmd)
-
-		logger.Debug("building url...")
-
-		url := fmt.Sprintf("%s://%s/commands", rootFlags.Protocol, rootFlags.Server)
-
-		logger.Debug("url built", zap.String("url", url))
-		logger.Debug("building command...")
-
-		command := fmt.Sprintf("active %s\ncompact", datastore)
 
-		logger.Debug("command built", zap.String("command", command))
-		logger.Debug("building request")
+		logger.Debug("building client...")
 
-		req, err := utils.NewRequest(http.MethodPost, url, rootFlags.Role, rootFlags.Password, strings.NewReader(command))
+		client, err := shared.NewClient(cmd)
 		if err != nil {
-			logger.Error("could not build request", zap.Error(err))
+			logger.Error("could not build client", zap.Error(err))
 			return err
 		}
 
-		req.Header.Set("Content-Type", "text/plain")
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("compacting datastore...")
 
-		logger.Debug("request built!", utils.RequestToLoggerFields(req)...)
-		logger.Debug("making request...")
-
-		res, err := client.Do(req)
+		payload, err := client.Compact(ctx, datastore)
 		if err != nil {
 			logger.Error("request failed", zap.Error(err))
 			return err
 		}
 
-		defer res.Body.Close()
-		logger.Debug("request complete!", utils.ResponseToLoggerFields(res)...)
-
-		payload, _ := io.ReadAll(res.Body)
-
-		if res.StatusCode > 299 {
-			logger.Error("bad status from server", zap.ByteString("response", payload))
-			return fmt.Errorf("bad response from server: %s", res.Status)
-		}
-
-		logger.Info("response from server", zap.ByteString("data", payload))
+		logger.Info("response from server", zap.String("data", payload))
 
 		return nil
 	}
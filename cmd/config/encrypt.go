@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/mick-roper/rdfox-cli/config"
+	"github.com/mick-roper/rdfox-cli/console"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func encryptPasswordCmd() *cobra.Command {
+	var cmd cobra.Command
+	var path string
+
+	cmd.Use = "encrypt-password"
+	cmd.Short = "stores a password in a file encrypted with a passphrase"
+	cmd.Long = "prompts for a password and a passphrase, and writes the encrypted password to a file that can be used with --password-file. " +
+		"The passphrase is read from RDFOX_CLI_PASSPHRASE if it is set."
+
+	cmd.Flags().StringVar(&path, "file", "", "the file to write the encrypted password to")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		if path == "" {
+			return errors.New("file is unset")
+		}
+
+		password, err := console.SecretPrompt("password:")
+		if err != nil {
+			logger.Error("could not read the password", zap.Error(err))
+			return err
+		}
+
+		if password == "" {
+			return errors.New("password is empty")
+		}
+
+		passphrase := config.PassphraseFromEnv()
+
+		if passphrase == "" {
+			if passphrase, err = console.SecretPrompt("passphrase:"); err != nil {
+				logger.Error("could not read the passphrase", zap.Error(err))
+				return err
+			}
+
+			confirm, err := console.SecretPrompt("confirm passphrase:")
+			if err != nil {
+				logger.Error("could not read the passphrase", zap.Error(err))
+				return err
+			}
+
+			if confirm != passphrase {
+				return errors.New("the passphrases do not match")
+			}
+		}
+
+		if passphrase == "" {
+			return errors.New("passphrase is empty")
+		}
+
+		logger.Debug("writing password file...", zap.String("file", path))
+
+		if err := config.WritePasswordFile(path, password, passphrase); err != nil {
+			logger.Error("could not write password file", zap.Error(err))
+			return err
+		}
+
+		logger.Info("password file written", zap.String("file", path))
+
+		return nil
+	}
+
+	return &cmd
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+
 	"github.com/mick-roper/rdfox-cli/config"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
@@ -13,6 +15,11 @@ type initCommandConfig struct {
 	role     string
 	password string
 	logLevel string
+
+	passwordCommand string
+	passwordFile    string
+
+	tls config.TLS
 }
 
 func (c initCommandConfig) Server() string {
@@ -35,6 +42,18 @@ func (c initCommandConfig) LogLevel() string {
 	return c.logLevel
 }
 
+func (c initCommandConfig) PasswordCommand() string {
+	return c.passwordCommand
+}
+
+func (c initCommandConfig) PasswordFile() string {
+	return c.passwordFile
+}
+
+func (c initCommandConfig) TLS() config.TLS {
+	return c.tls
+}
+
 func initCmd() *cobra.Command {
 	var x initCommandConfig
 	var path string
@@ -43,16 +62,26 @@ func initCmd() *cobra.Command {
 	var cmd cobra.Command
 	cmd.Use = "init"
 	cmd.Short = "initialises the config"
+	cmd.Long = "initialises the config - use --profile to write a named profile instead of the default one"
 
 	cmd.Flags().StringVar(&x.server, "server", "", "the name of the server")
 	cmd.Flags().StringVar(&x.protocol, "protocol", "https", "the protocol to sue to communicate with the server")
 	cmd.Flags().StringVar(&x.role, "role", "", "the role to use to connect to the server")
 	cmd.Flags().StringVar(&x.password, "password", "", "the password to use to connect to the server")
+	cmd.Flags().StringVar(&x.passwordCommand, "password-command", "", "a command that prints the password, instead of storing the password")
+	cmd.Flags().StringVar(&x.passwordFile, "password-file", "", "a password file written by 'config encrypt-password', instead of storing the password")
+	cmd.Flags().StringVar(&x.tls.CACert, "ca-cert", "", "a PEM bundle of CA certificates to trust when connecting to the server")
+	cmd.Flags().StringVar(&x.tls.ClientCert, "client-cert", "", "a PEM client certificate for mutual TLS")
+	cmd.Flags().StringVar(&x.tls.ClientKey, "client-key", "", "the PEM private key of the client certificate")
+	cmd.Flags().StringVar(&x.tls.MinVersion, "tls-min-version", "", "the minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	cmd.Flags().StringVar(&x.tls.ServerName, "tls-server-name", "", "the name used to verify the server's certificate, if it differs from the server")
+	cmd.Flags().StringVar(&x.tls.Insecure, "insecure", "", "<true> to skip verifying the server's certificate")
 	cmd.Flags().StringVar(&x.logLevel, "default-log-level", "info", "the log level to use as default")
 	cmd.Flags().StringVar(&path, "path", config.DefaultFilePath(), "the config file path")
 	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "<true> to overwrite an existing config")
 
-	cmd.MarkFlagsRequiredTogether("server", "role", "password")
+	cmd.MarkFlagsRequiredTogether("server", "role")
+	cmd.MarkFlagsMutuallyExclusive("password", "password-command", "password-file")
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
@@ -65,9 +94,15 @@ func initCmd() *cobra.Command {
 			return err
 		}
 
+		if x.server != "" && x.password == "" && x.passwordCommand == "" && x.passwordFile == "" {
+			return errors.New("one of password, password-command or password-file must be set")
+		}
+
 		logger.Debug("flags are valid - writing the config to file...")
 
-		if err := config.WriteFile(ctx, path, x, overwrite); err != nil {
+		profile := cmd.Flags().Lookup("profile").Value.String()
+
+		if err := config.WriteFile(ctx, path, profile, x, overwrite); err != nil {
 			logger.Error("could not write config file", zap.Error(err))
 			return err
 		}
@@ -19,16 +19,38 @@ func printCmd() *cobra.Command {
 
 		logger.Debug("getting flags...")
 
+		profile := cmd.Flags().Lookup("profile").Value.String()
+		if profile == "" {
+			profile = config.ProfileFromEnv()
+		}
+
+		if profile == "" {
+			if _, current, err := config.ListProfiles(path); err == nil {
+				profile = current
+			}
+		}
+
 		server := cmd.Flags().Lookup("server").Value.String()
 		protocol := cmd.Flags().Lookup("protocol").Value.String()
 		role := cmd.Flags().Lookup("role").Value.String()
 		password := cmd.Flags().Lookup("password").Value.String()
 		logLevel := cmd.Flags().Lookup("log-level").Value.String()
+		passwordCommand := cmd.Flags().Lookup("password-command").Value.String()
+		passwordFile := cmd.Flags().Lookup("password-file").Value.String()
 
 		logger.Info("got config",
+			zap.String("profile", profile),
 			zap.String("server", server),
 			zap.String("role", role),
-			zap.String("password", password),
+			zap.String("password", utils.Redact(password)),
+			zap.String("password-command", passwordCommand),
+			zap.String("password-file", passwordFile),
+			zap.String("ca-cert", cmd.Flags().Lookup("ca-cert").Value.String()),
+			zap.String("client-cert", cmd.Flags().Lookup("client-cert").Value.String()),
+			zap.String("client-key", cmd.Flags().Lookup("client-key").Value.String()),
+			zap.String("tls-min-version", cmd.Flags().Lookup("tls-min-version").Value.String()),
+			zap.String("tls-server-name", cmd.Flags().Lookup("tls-server-name").Value.String()),
+			zap.String("insecure", cmd.Flags().Lookup("insecure").Value.String()),
 			zap.String("protocol", protocol),
 			zap.String("log-level", logLevel),
 		)
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"github.com/mick-roper/rdfox-cli/config"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func useProfileCmd() *cobra.Command {
+	var cmd cobra.Command
+	var path string
+
+	cmd.Use = "use-profile <name>"
+	cmd.Short = "sets the profile used when --profile and RDFOX_CLI_PROFILE are not set"
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.Flags().StringVar(&path, "path", config.DefaultFilePath(), "the config file path")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		profile := args[0]
+
+		logger.Debug("setting the current profile...", zap.String("profile", profile))
+
+		if err := config.UseProfile(ctx, path, profile); err != nil {
+			logger.Error("could not set the current profile", zap.Error(err))
+			return err
+		}
+
+		logger.Info("current profile set", zap.String("profile", profile))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func listProfilesCmd() *cobra.Command {
+	var cmd cobra.Command
+	var path string
+
+	cmd.Use = "list-profiles"
+	cmd.Short = "lists the profiles in the config file"
+
+	cmd.Flags().StringVar(&path, "path", config.DefaultFilePath(), "the config file path")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading profiles...")
+
+		profiles, current, err := config.ListProfiles(path)
+		if err != nil {
+			logger.Error("could not read profiles", zap.Error(err))
+			return err
+		}
+
+		logger.Info("got profiles", zap.Strings("profiles", profiles), zap.String("current", current))
+
+		return nil
+	}
+
+	return &cmd
+}
@@ -4,13 +4,21 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// AnnotationManagesConfig marks commands that read or write the config file themselves, so they run
+// even when the selected profile does not exist yet.
+const AnnotationManagesConfig = "manages-config"
+
 func Cmd() *cobra.Command {
 	var cmd cobra.Command
 	cmd.Use = "config"
 	cmd.Short = "configures the CLI"
+	cmd.Annotations = map[string]string{AnnotationManagesConfig: "true"}
 
 	cmd.AddCommand(printCmd())
 	cmd.AddCommand(initCmd())
+	cmd.AddCommand(useProfileCmd())
+	cmd.AddCommand(listProfilesCmd())
+	cmd.AddCommand(encryptPasswordCmd())
 
 	return &cmd
 }
@@ -0,0 +1,18 @@
+package datastore
+
+import "github.com/spf13/cobra"
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "datastore"
+	cmd.Short = "manage datastores"
+	cmd.Long = "provides datastore lifecycle functionality"
+
+	cmd.AddCommand(listDatastores())
+	cmd.AddCommand(createDatastore())
+	cmd.AddCommand(deleteDatastore())
+	cmd.AddCommand(getInfo())
+
+	return &cmd
+}
@@ -0,0 +1,119 @@
+package datastore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func createDatastore() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "create"
+	cmd.Short = "create a new datastore"
+	cmd.Long = "creates a new datastore. Parameters such as type, equality, persistence and max-data-pool-size are passed as --param key=value, or read from a file of key=value lines."
+
+	var datastore string
+	var params []string
+	var paramsFile string
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the name of the new datastore")
+	cmd.Flags().StringArrayVar(&params, "param", nil, "a datastore parameter as key=value (can be repeated)")
+	cmd.Flags().StringVar(&paramsFile, "params-file", "", "a file of key=value datastore parameters, one per line")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		if datastore == "" {
+			logger.Error("arg not set", zap.String("arg", "datastore"))
+			return errors.New("arg not set")
+		}
+
+		logger.Debug("reading parameters...")
+
+		parameters := map[string]string{}
+
+		if paramsFile != "" {
+			if err := readParamsFile(paramsFile, parameters); err != nil {
+				logger.Error("could not read parameters file", zap.Error(err))
+				return err
+			}
+		}
+
+		// parameters on the command line take precedence over the file
+		for _, p := range params {
+			if err := parseParam(p, parameters); err != nil {
+				logger.Error("invalid parameter", zap.Error(err))
+				return err
+			}
+		}
+
+		logger.Debug("parameters read", zap.Any("parameters", parameters))
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("creating datastore...")
+
+		if err := client.CreateDatastore(ctx, v6.CreateDatastoreRequest{Name: datastore, Parameters: parameters}); err != nil {
+			logger.Error("could not create datastore", zap.Error(err))
+			return err
+		}
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func parseParam(s string, dst map[string]string) error {
+	parts := strings.SplitN(s, "=", 2)
+
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("parameter must be key=value: %s", s)
+	}
+
+	dst[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+
+	return nil
+}
+
+func readParamsFile(path string, dst map[string]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Split(bufio.ScanLines)
+
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+
+		if err := parseParam(s, dst); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,60 @@
+package datastore
+
+import (
+	"errors"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/console"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func deleteDatastore() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "delete"
+	cmd.Short = "deletes a datastore"
+
+	var datastore string
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the name of the datastore that should be deleted")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		if datastore == "" {
+			logger.Error("arg not set", zap.String("arg", "datastore"))
+			return errors.New("arg not set")
+		}
+
+		logger.Debug("asking for confirmation...")
+
+		if ok := console.BoolPrompt("are you sure you want to delete the datastore '" + datastore + "' and all of its data?"); !ok {
+			logger.Info("you must provide confirmation that you want to delete the datastore")
+			return nil
+		}
+
+		logger.Debug("got confirmation")
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("deleting datastore...")
+
+		if err := client.DeleteDatastore(ctx, datastore); err != nil {
+			logger.Error("could not delete datastore", zap.Error(err))
+			return err
+		}
+
+		return nil
+	}
+
+	return &cmd
+}
@@ -0,0 +1,93 @@
+package datastore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func getInfo() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "info"
+	cmd.Short = "get info about a datastore"
+
+	var datastore string
+	var format string
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the name of the datastore to inspect")
+	cmd.Flags().StringVar(&format, "format", "console", "The format of the results (console, json).")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		if datastore == "" {
+			logger.Error("arg not set", zap.String("arg", "datastore"))
+			return errors.New("arg not set")
+		}
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("getting datastore info...")
+
+		info, err := client.GetDatastoreInfo(ctx, datastore)
+		if err != nil {
+			logger.Error("could not get datastore info", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("got datastore info")
+
+		if format == "json" {
+			return json.NewEncoder(os.Stdout).Encode(info)
+		}
+
+		printInfo(info)
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func printInfo(info v6.Statistics) {
+	components := make([]string, 0, len(info))
+	for c := range info {
+		components = append(components, c)
+	}
+
+	sort.Strings(components)
+
+	for _, component := range components {
+		fmt.Print(component)
+
+		properties := make([]string, 0, len(info[component]))
+		for p := range info[component] {
+			properties = append(properties, p)
+		}
+
+		sort.Strings(properties)
+
+		for _, p := range properties {
+			fmt.Print("\n\t", p, ":\t", info[component][p])
+		}
+
+		fmt.Print("\n")
+	}
+}
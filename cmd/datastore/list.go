@@ -0,0 +1,45 @@
+package datastore
+
+import (
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func listDatastores() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "list"
+	cmd.Short = "lists all datastores"
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("getting datastores...")
+
+		datastores, err := client.ListDatastores(ctx)
+		if err != nil {
+			logger.Error("could not get datastores", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("got datastores", zap.Int("count", len(datastores)))
+
+		logger.Info("got datastores", zap.Strings("datastores", datastores))
+
+		return nil
+	}
+
+	return &cmd
+}
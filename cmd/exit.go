@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"net"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+)
+
+// exit codes returned by Execute so that scripts can branch on the class of failure
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitBadRequest   = 2
+	exitUnauthorized = 3
+	exitNotFound     = 4
+	exitConflict     = 5
+	exitUnreachable  = 6
+
+	// exitInterrupted is what shells return for a command stopped by SIGINT
+	exitInterrupted = 130
+)
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var netErr net.Error
+
+	switch {
+	case v6.IsBadRequest(err):
+		return exitBadRequest
+	case v6.IsUnauthorized(err):
+		return exitUnauthorized
+	case v6.IsNotFound(err):
+		return exitNotFound
+	case v6.IsConflict(err):
+		return exitConflict
+	case errors.As(err, &netErr):
+		return exitUnreachable
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,178 @@
+package explain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/ttl"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	formatTree = "tree"
+	formatJSON = "json"
+	formatDOT  = "dot"
+)
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var fact string
+	var explanationType string
+	var maxDepth int
+	var maxRuleInstances int
+	var format string
+	var output string
+
+	cmd.Use = "explain"
+	cmd.Short = "explain how a fact was derived"
+	cmd.Long = `explains how reasoning derived a fact, showing the rules that fired, the values they fired with, and
+the facts they matched, down to explicit facts.
+
+The fact is a triple, e.g. '<http://ex.com/a> a <http://ex.com/Person>', which may use the
+datastore's prefixes, or a Datalog atom, e.g. '[ex:a, a, ex:Person]'.`
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore that holds the fact")
+	cmd.Flags().StringVar(&fact, "fact", "", "the fact to explain")
+	cmd.Flags().StringVar(&explanationType, "type", string(v6.ExplainShortest), "shortest for one shortest proof, to-explicit for every proof from explicit facts, or exhaustive for every proof")
+	cmd.Flags().IntVar(&maxDepth, "max-depth", 0, "the deepest the proof may go (defaults to the server's limit)")
+	cmd.Flags().IntVar(&maxRuleInstances, "max-rule-instances", 0, "the most rule instances shown for each fact (defaults to the server's limit)")
+	cmd.Flags().StringVar(&format, "format", formatTree, "the output format: tree, json or dot (Graphviz)")
+	cmd.Flags().StringVar(&output, "output", "", "the file to write the explanation to (defaults to stdout)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		if fact == "" {
+			return errors.New("fact is unset")
+		}
+
+		switch v6.ExplanationType(explanationType) {
+		case v6.ExplainShortest, v6.ExplainToExplicit, v6.ExplainExhaustive:
+		default:
+			return fmt.Errorf("unknown type: %s", explanationType)
+		}
+
+		switch format {
+		case formatTree, formatJSON, formatDOT:
+		default:
+			return fmt.Errorf("unknown format: %s", format)
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+
+		atom := strings.TrimSpace(fact)
+
+		if !strings.HasPrefix(atom, "[") && !strings.HasSuffix(atom, "]") {
+			atom, err = tripleAtom(atom, shared.DatastorePrefixes(ctx, client, datastore))
+			if err != nil {
+				logger.Error("could not read fact", zap.Error(err))
+				return err
+			}
+		}
+
+		logger.Debug("explaining fact...", zap.String("fact", atom))
+
+		explanation, err := client.Explain(ctx, v6.ExplainRequest{
+			Datastore:        datastore,
+			Fact:             atom,
+			Type:             v6.ExplanationType(explanationType),
+			MaxDepth:         maxDepth,
+			MaxRuleInstances: maxRuleInstances,
+		})
+		if err != nil {
+			logger.Error("could not explain fact", zap.Error(err))
+			return err
+		}
+
+		if len(explanation.Facts) == 0 {
+			return errors.New("the server returned an empty explanation")
+		}
+
+		if !explanation.Complete {
+			logger.Warn("the explanation is incomplete - raise --max-depth or --max-rule-instances to see more")
+		}
+
+		var dst io.Writer = os.Stdout
+
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				logger.Error("could not create output file", zap.Error(err))
+				return err
+			}
+
+			defer f.Close()
+
+			dst = f
+		}
+
+		switch format {
+		case formatJSON:
+			enc := json.NewEncoder(dst)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(explanation)
+		case formatDOT:
+			err = writeDOT(explanation, dst)
+		default:
+			err = writeTree(explanation, dst)
+		}
+
+		if err != nil {
+			logger.Error("could not write explanation", zap.Error(err))
+			return err
+		}
+
+		return nil
+	}
+
+	return &cmd
+}
+
+// tripleAtom turns a triple into the Datalog atom the explanation endpoint expects, expanding any
+// prefixed names with the prefixes.
+func tripleAtom(triple string, prefixes ttl.Prefixes) (string, error) {
+	var sb strings.Builder
+
+	for name, iri := range prefixes {
+		sb.WriteString("@prefix " + name + ": " + ttl.IRI(iri).String() + " .\n")
+	}
+
+	sb.WriteString(strings.TrimSuffix(triple, "."))
+	sb.WriteString(" .\n")
+
+	triples, _, err := ttl.Read(strings.NewReader(sb.String()))
+	if err != nil {
+		return "", fmt.Errorf("the fact must be a single triple or a datalog atom: %w", err)
+	}
+
+	if len(triples) != 1 {
+		return "", fmt.Errorf("the fact must be a single triple but it is %d", len(triples))
+	}
+
+	t := triples[0]
+
+	return "[" + t.Subject.String() + ", " + t.Predicate.String() + ", " + t.Object.String() + "]", nil
+}
@@ -0,0 +1,118 @@
+package explain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+)
+
+// writeTree writes the explanation as an indented tree. A fact that has already been explained is
+// not expanded again, so shared facts and recursive rules do not repeat.
+func writeTree(e *v6.Explanation, dst io.Writer) error {
+	w := bufio.NewWriter(dst)
+	expanded := map[int]bool{}
+
+	var write func(id int, depth int)
+	write = func(id int, depth int) {
+		indent := strings.Repeat("    ", depth)
+
+		fact, ok := e.Fact(id)
+		if !ok {
+			fmt.Fprintf(w, "%s#%d (missing from the explanation)\n", indent, id)
+			return
+		}
+
+		if expanded[id] && len(fact.RuleInstances) > 0 {
+			fmt.Fprintf(w, "%s%s  (%s, explained above)\n", indent, fact.Fact, fact.Type)
+			return
+		}
+
+		expanded[id] = true
+
+		fmt.Fprintf(w, "%s%s  (%s)\n", indent, fact.Fact, fact.Type)
+
+		for _, ri := range fact.RuleInstances {
+			fmt.Fprintf(w, "%s  rule:     %s\n", indent, ri.Rule)
+
+			if ri.GroundedRule != "" {
+				fmt.Fprintf(w, "%s  grounded: %s\n", indent, ri.GroundedRule)
+			}
+
+			for _, body := range ri.BodyFacts {
+				if body != nil {
+					write(*body, depth+1)
+				}
+			}
+		}
+	}
+
+	write(rootFact(e), 0)
+
+	return w.Flush()
+}
+
+// writeDOT writes the explanation as a Graphviz digraph. Facts point to the rule instances that derived
+// them, which point to the facts that matched their bodies. Explicit facts are filled.
+func writeDOT(e *v6.Explanation, dst io.Writer) error {
+	w := bufio.NewWriter(dst)
+
+	fmt.Fprintln(w, "digraph explanation {")
+	fmt.Fprintln(w, "    rankdir=TB;")
+	fmt.Fprintln(w, `    node [fontname="monospace"];`)
+
+	for _, fact := range e.Facts {
+		style := `shape=box`
+
+		switch fact.Type {
+		case "explicit":
+			style += `, style=filled, fillcolor="lightgrey"`
+		case "false":
+			style += `, style=dashed`
+		}
+
+		if fact.ID == rootFact(e) {
+			style += `, penwidth=2`
+		}
+
+		fmt.Fprintf(w, "    f%d [label=%s, %s];\n", fact.ID, dotString(fact.Fact), style)
+
+		for i, ri := range fact.RuleInstances {
+			node := fmt.Sprintf("r%d_%d", fact.ID, i)
+			label := ri.GroundedRule
+			if label == "" {
+				label = ri.Rule
+			}
+
+			fmt.Fprintf(w, "    %s [label=%s, shape=note, tooltip=%s];\n", node, dotString(label), dotString(ri.Rule))
+			fmt.Fprintf(w, "    f%d -> %s;\n", fact.ID, node)
+
+			for _, body := range ri.BodyFacts {
+				if body != nil {
+					fmt.Fprintf(w, "    %s -> f%d;\n", node, *body)
+				}
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "}")
+
+	return w.Flush()
+}
+
+// rootFact is the ID of the fact being explained.
+func rootFact(e *v6.Explanation) int {
+	if _, ok := e.Fact(0); ok {
+		return 0
+	}
+
+	return e.Facts[0].ID
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotString(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
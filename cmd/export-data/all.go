@@ -0,0 +1,84 @@
+package exportdata
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+var datastoreFormats = map[string]string{
+	"trig": v6.MediaTypeTriG,
+	"nq":   v6.MediaTypeNQuads,
+}
+
+// exportDatastore writes every graph in the datastore to filePath, and optionally the rules and
+// axioms to their own files, using the content endpoint rather than a cursor.
+func exportDatastore(ctx context.Context, client *v6.Client, datastore, format, filePath, rulesPath, axiomsPath string) error {
+	logger := utils.LoggerFromContext(ctx)
+
+	mediaType, ok := datastoreFormats[format]
+	if !ok {
+		return fmt.Errorf("format %s cannot be used to export a whole datastore - use trig or nq", format)
+	}
+
+	exports := []struct {
+		name      string
+		path      string
+		mediaType string
+	}{
+		{"data", filePath, mediaType},
+		{"rules", rulesPath, v6.MediaTypeDatalog},
+		{"axioms", axiomsPath, v6.MediaTypeOWL},
+	}
+
+	for _, e := range exports {
+		if e.path == "" {
+			continue
+		}
+
+		logger := logger.With(zap.String("export", e.name), zap.String("file", e.path))
+
+		export := func() error {
+			return exportContent(ctx, client, v6.ExportRequest{Datastore: datastore, Accept: e.mediaType}, e.path)
+		}
+
+		logger.Info("exporting...")
+
+		if err := utils.DoWithTicker(export, func() {
+			logger.Info("still exporting...")
+		}); err != nil {
+			logger.Error("could not export", zap.Error(err))
+			return err
+		}
+
+		logger.Info("export complete")
+	}
+
+	return nil
+}
+
+func exportContent(ctx context.Context, client *v6.Client, req v6.ExportRequest, path string) error {
+	res, err := client.ExportContent(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	f, err := openExportFile(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if _, err := io.Copy(f, res.Body); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
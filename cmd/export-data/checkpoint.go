@@ -0,0 +1,118 @@
+package exportdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+// checkpoint records how much of an export has been committed to disk, so that an interrupted export
+// can be resumed. It is written next to the export file after every batch and removed on success.
+type checkpoint struct {
+	Datastore string `json:"datastore"`
+	Graph     string `json:"graph"`
+	Format    string `json:"format"`
+	Ordered   bool   `json:"ordered"`
+
+	// Rows is the number of result rows that have been written and flushed.
+	Rows int64 `json:"rows"`
+
+	// Bytes is the size of the export file after the last flushed batch.
+	Bytes int64 `json:"bytes"`
+
+	// Prefixes are the prefixes in the header of the export file, so a resumed export compacts IRIs
+	// with the same prefixes even if the datastore's have changed. Nil means there are none.
+	Prefixes ttl.Prefixes `json:"prefixes"`
+}
+
+func checkpointPath(exportPath string) string {
+	return exportPath + ".checkpoint"
+}
+
+func loadCheckpoint(exportPath string) (*checkpoint, error) {
+	b, err := os.ReadFile(checkpointPath(exportPath))
+	if os.IsNotExist(err) {
+		return nil, errors.New("there is no checkpoint to resume from")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var c checkpoint
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("could not read checkpoint: %w", err)
+	}
+
+	return &c, nil
+}
+
+// save writes the checkpoint to a temporary file and renames it, so a crash never leaves a partial checkpoint.
+func (c *checkpoint) save(exportPath string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	path := checkpointPath(exportPath)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func (c *checkpoint) matches(other checkpoint) error {
+	if c.Datastore != other.Datastore || c.Graph != other.Graph || c.Format != other.Format || c.Ordered != other.Ordered {
+		return fmt.Errorf("the checkpoint is for a different export (datastore %s, graph %s, format %s, ordered %t)", c.Datastore, c.Graph, c.Format, c.Ordered)
+	}
+
+	return nil
+}
+
+// matchesPrefixes checks that prefixes given for a resumed export are the ones its file was started
+// with, since the header has already been written.
+func (c *checkpoint) matchesPrefixes(prefixes ttl.Prefixes) error {
+	for name, iri := range prefixes {
+		if got, ok := c.Prefixes[name]; !ok || got != iri {
+			return fmt.Errorf("prefix %s: <%s> is not in the checkpoint - a resumed export keeps the prefixes it was started with", name, iri)
+		}
+	}
+
+	return nil
+}
+
+func removeCheckpoint(exportPath string) error {
+	if err := os.Remove(checkpointPath(exportPath)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// openResumeFile opens an existing export file, discards anything written after the last checkpoint
+// and positions the file for appending.
+func openResumeFile(path string, size int64) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
@@ -7,7 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	"github.com/mick-roper/rdfox-cli/ttl"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
@@ -21,148 +21,275 @@ func Cmd() *cobra.Command {
 	var filePath string
 	var limit int
 	var graph string
+	var all bool
+	var format string
+	var rulesPath string
+	var axiomsPath string
+	var base string
+	var prefixes []string
+	var resume bool
+	var ordered bool
+	var partitions int
+	var partitionPrefixes []string
+	var shards bool
+	var datastorePrefixes bool
 
 	cmd.Use = "export-data"
 	cmd.Short = "export data from the database"
-	cmd.Long = "TODO: write sometihn inspiring here!"
+	cmd.Long = `exports the data in a datastore to a file. There are four ways to run an export:
+
+A single graph - the default. The triples in --graph are read over a cursor, --limit at a time, and
+written as Turtle, N-Triples or N-Quads. Turtle output is compacted with the --prefix prefixes and
+the datastore's own prefixes.
+
+  rdfox-cli export-data --datastore production --graph http://example.com/g --file g.ttl
+
+The whole datastore - with --all, the default graph and every named graph are exported in one
+request as TriG or N-Quads, optionally with the rules and axioms in their own files.
+
+  rdfox-cli export-data --datastore production --all --rules-file rules.dlog
+
+Resumable - with --ordered, the server sorts the graph so that the export can be continued from its
+checkpoint file (e.g. g.ttl.checkpoint) with --resume if it is interrupted. Sorting the whole graph
+is slow for large graphs, so exports are unordered unless --ordered is set.
+
+  rdfox-cli export-data --datastore production --graph http://example.com/g --file g.ttl --ordered
+  rdfox-cli export-data --datastore production --graph http://example.com/g --file g.ttl --ordered --resume
+
+Partitioned - with --partitions or --partition-prefix, the graph is split by subject and the
+partitions are read concurrently, each over its own cursor. They are merged into one file, or
+written to a file each with --shards. Partitioned exports cannot be resumed.
+
+  rdfox-cli export-data --datastore production --graph http://example.com/g --partitions 4 --shards`
 
 	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore that contains the data you want to export")
 	cmd.Flags().StringVar(&filePath, "file", "export.ttl", "the file that the exported data will be written to")
 	cmd.Flags().IntVar(&limit, "limit", 5000, "the maximum number of triples to return in a single cursor request")
 	cmd.Flags().StringVar(&graph, "graph", "", "the graph that contains the data you want to export")
+	cmd.Flags().BoolVar(&all, "all", false, "<true> to export the whole datastore (the default graph and all named graphs) instead of a single graph")
+	cmd.Flags().StringVar(&format, "format", "", "the export format: ttl (default), nt or nq for a single graph; trig (default) or nq with --all")
+	cmd.Flags().StringVar(&rulesPath, "rules-file", "", "with --all, also export the datalog rules to this file")
+	cmd.Flags().StringVar(&axiomsPath, "axioms-file", "", "with --all, also export the axioms (OWL functional syntax) to this file")
+	cmd.Flags().StringVar(&base, "base", "", "the base IRI written to Turtle output - IRIs under it are written relative to it")
+	cmd.Flags().StringArrayVar(&prefixes, "prefix", nil, "a prefix used to compact IRIs in Turtle output, as name=iri (can be repeated)")
+	cmd.Flags().BoolVar(&datastorePrefixes, "datastore-prefixes", true, "<false> to only compact IRIs in Turtle output with --prefix, not with the datastore's prefixes")
+	cmd.Flags().BoolVar(&resume, "resume", false, "<true> to continue an interrupted export from its checkpoint, appending to the existing file")
+	cmd.Flags().IntVar(&partitions, "partitions", 1, "split the graph by subject hash into this many partitions and read them concurrently, each over its own cursor")
+	cmd.Flags().StringArrayVar(&partitionPrefixes, "partition-prefix", nil, "split the graph by subject IRI prefix instead of by hash - one partition per prefix plus one for everything else (can be repeated)")
+	cmd.Flags().BoolVar(&shards, "shards", false, "<true> to write each partition to its own file (e.g. export.0.ttl) instead of merging them into one")
+	cmd.Flags().BoolVar(&ordered, "ordered", false, "<true> to sort the export so that it can be continued with --resume if it is interrupted - the server sorts the whole graph, which is slow for large graphs")
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		if datastore == "" {
 			return errors.New("datastore is unset")
 		}
 
-		if graph == "" {
-			return errors.New("graph is unset")
+		if all && graph != "" {
+			return errors.New("graph cannot be set with all")
 		}
 
-		graph = strings.TrimPrefix(graph, "<")
-		graph = strings.TrimSuffix(graph, ">")
+		if !all && graph == "" {
+			return errors.New("graph is unset")
+		}
 
-		ctx := cmd.Context()
-		logger := utils.LoggerFromContext(ctx)
+		if all && resume {
+			return errors.New("resume cannot be used with all")
+		}
 
-		logger.Debug("getting flags...")
+		partitioned := partitions != 1 || len(partitionPrefixes) > 0
 
-		server := cmd.Flags().Lookup("server").Value.String()
-		protocol := cmd.Flags().Lookup("protocol").Value.String()
-		role := cmd.Flags().Lookup("role").Value.String()
-		password := cmd.Flags().Lookup("password").Value.String()
+		if partitioned && all {
+			return errors.New("partitions cannot be used with all")
+		}
 
-		logger.Debug("got flags", zap.String("server", server), zap.String("protocol", protocol), zap.String("role", role), zap.String("password", password))
-		logger.Debug("creating a connection...")
+		if partitioned && (resume || ordered) {
+			return errors.New("partitions cannot be used with resume or ordered")
+		}
 
-		connectionID, err := v6.CreateConnection(ctx, server, protocol, role, password, datastore)
-		if err != nil {
-			logger.Error("could not create a connection", zap.Error(err))
-			return err
+		// without ORDER BY the server may return the rows in a different order, so OFFSET would skip
+		// or repeat rows
+		if resume && !ordered {
+			return errors.New("resume can only be used with ordered")
 		}
 
-		defer func() {
-			logger.Debug("deleting the connection...")
+		if partitions != 1 && len(partitionPrefixes) > 0 {
+			return errors.New("partitions and partition-prefix cannot both be set")
+		}
 
-			if err := v6.DeleteConnection(ctx, server, protocol, role, password, datastore, connectionID); err != nil {
-				logger.Error("could not delete connection", zap.Error(err))
-			}
+		if shards && !partitioned {
+			return errors.New("shards can only be used with partitions or partition-prefix")
+		}
 
-			logger.Debug("connection deleted!")
-		}()
+		if !all && (rulesPath != "" || axiomsPath != "") {
+			return errors.New("rules-file and axioms-file can only be used with all")
+		}
 
-		logger.Debug("connection created", zap.String("connection-id", connectionID))
+		if !all && format == "" {
+			format = "ttl"
+		}
 
-		logger.Debug("building query...")
-		query := fmt.Sprintf("SELECT ?s ?p ?o FROM <%s> WHERE { ?s ?p ?o }", graph)
-		logger.Debug("query built", zap.String("query", query))
+		if !all && !isGraphFormat(format) {
+			return fmt.Errorf("format %s cannot be used to export a single graph - use one of %v", format, graphFormats)
+		}
 
-		logger.Debug("creating a cursor...")
+		graph = strings.TrimPrefix(graph, "<")
+		graph = strings.TrimSuffix(graph, ">")
 
-		cursorID, err := v6.CreateCursor(ctx, server, protocol, role, password, datastore, connectionID, query)
+		flagPrefixes, err := parsePrefixes(prefixes)
 		if err != nil {
-			logger.Error("could not create a cursor", zap.Error(err))
 			return err
 		}
 
-		defer func() {
-			logger.Debug("deleting cursor...")
-
-			if err := v6.DeleteCursor(ctx, server, protocol, role, password, datastore, connectionID, cursorID); err != nil {
-				logger.Error("could not close the cursor", zap.Error(err))
-			}
+		prefixMap := ttl.Prefixes{}
+		for name, iri := range flagPrefixes {
+			prefixMap[name] = iri
+		}
 
-			logger.Debug("cursor deleted!")
-		}()
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
 
-		logger.Debug("cursor created", zap.String("cursorID", cursorID))
+		logger.Debug("building client...")
 
-		logger.Debug("opening file for export...")
-		f, err := openExportFile(filePath)
+		client, err := shared.NewClient(cmd)
 		if err != nil {
-			logger.Error("could not create export file", zap.Error(err))
+			logger.Error("could not build client", zap.Error(err))
 			return err
 		}
 
-		defer func() {
-			logger.Debug("closing file...")
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
 
-			if err := f.Close(); err != nil {
-				logger.Error("could not close file", zap.Error(err))
+		if all {
+			if format == "" {
+				format = "trig"
 			}
 
-			logger.Debug("file closed")
-		}()
+			if !cmd.Flags().Changed("file") {
+				filePath = "export." + format
+			}
 
-		logger.Info("getting data...")
+			return exportDatastore(ctx, client, datastore, format, filePath, rulesPath, axiomsPath)
+		}
 
-		dataChan := make(chan map[string]map[string][]string)
-		readDoneChan := make(chan struct{})
-		writeDoneChan := make(chan struct{})
-
-		write := func() {
-			defer close(writeDoneChan)
-			for {
-				select {
-				case triples := <-dataChan:
-					writeFile := func() error {
-						if err := ttl.Write(triples, f); err != nil {
-							return err
-						}
-
-						return nil
-					}
-
-					logger.Info("writing data to file...")
-
-					if err := utils.DoWithTicker(writeFile, func() {
-						logger.Info("still writing file...")
-					}); err != nil {
-						logger.Error("could not write data", zap.Error(err))
-						return
-					}
-
-					logger.Info("write complete")
-				case <-readDoneChan:
-					return
+		if !cmd.Flags().Changed("file") {
+			filePath = "export." + format
+		}
+
+		// prefixes given with --prefix win over the datastore's, and a resumed export uses the prefixes
+		// from its checkpoint instead
+		if datastorePrefixes && format == "ttl" && !resume {
+			for name, iri := range shared.DatastorePrefixes(ctx, client, datastore) {
+				if _, ok := prefixMap[name]; !ok {
+					prefixMap[name] = iri
 				}
 			}
 		}
 
-		go write()
+		if partitioned {
+			filters := prefixPartitions(partitionPrefixes)
 
-		readData := func() error {
-			defer close(readDoneChan)
-			defer close(dataChan)
+			if len(partitionPrefixes) == 0 {
+				filters, err = hashPartitions(partitions)
+				if err != nil {
+					return err
+				}
+			}
+
+			logger.Info("exporting partitions", zap.Int("partitions", len(filters)), zap.Bool("shards", shards))
+
+			return exportPartitions(ctx, client, partitionedExport{
+				datastore: datastore,
+				graph:     graph,
+				format:    format,
+				filePath:  filePath,
+				base:      base,
+				prefixes:  prefixMap,
+				limit:     limit,
+				filters:   filters,
+				shards:    shards,
+			})
+		}
+
+		progress := checkpoint{Datastore: datastore, Graph: graph, Format: format, Ordered: ordered, Prefixes: prefixMap}
+
+		if resume {
+			logger.Debug("loading checkpoint...")
+
+			cp, err := loadCheckpoint(filePath)
+			if err != nil {
+				logger.Error("could not load checkpoint", zap.Error(err))
+				return err
+			}
+
+			if !cp.Ordered {
+				err := errors.New("the checkpoint is for an unordered export, which cannot be resumed without skipping or repeating rows - start the export again")
+				logger.Error("cannot resume export", zap.Error(err))
+				return err
+			}
 
-			handle := func(data map[string]map[string][]string) {
-				dataChan <- data
+			if err := cp.matches(progress); err != nil {
+				logger.Error("checkpoint does not match the export", zap.Error(err))
+				return err
 			}
 
+			progress = *cp
+
+			// the header has already been written, so the rest of the file must use the same prefixes
+			if err := cp.matchesPrefixes(flagPrefixes); err != nil {
+				logger.Error("checkpoint does not match the export", zap.Error(err))
+				return err
+			}
+
+			prefixMap = cp.Prefixes
+
+			logger.Info("resuming export", zap.Int64("rows", progress.Rows), zap.Int64("bytes", progress.Bytes))
+		}
+
+		logger.Debug("building query...")
+		query := graphQuery(graph, "")
+
+		if ordered {
+			query += " ORDER BY ?s ?p ?o"
+		}
+
+		if progress.Rows > 0 {
+			query += fmt.Sprintf(" OFFSET %d", progress.Rows)
+		}
+
+		logger.Debug("query built", zap.String("query", query))
+
+		logger.Debug("opening file for export...")
+
+		var f *os.File
+		if resume {
+			f, err = openResumeFile(filePath, progress.Bytes)
+		} else {
+			f, err = openExportFile(filePath)
+		}
+
+		if err != nil {
+			logger.Error("could not create export file", zap.Error(err))
+			return err
+		}
+
+		writer, err := newRowWriter(format, f, graph, base, prefixMap, progress.Rows > 0)
+		if err != nil {
+			logger.Error("could not create writer", zap.Error(err))
+			f.Close()
+			return err
+		}
+
+		if err := progress.save(filePath); err != nil {
+			logger.Error("could not write checkpoint", zap.Error(err))
+			f.Close()
+			return err
+		}
+
+		s := newSink(logger, f, writer, &progress, 1)
+
+		readData := func() error {
 			logger.Info("reading data from the server...")
 
-			if err := v6.ReadWithCursor(ctx, server, protocol, role, password, datastore, connectionID, cursorID, limit, handle); err != nil {
+			if err := readGraph(ctx, client, datastore, query, limit, s.send); err != nil {
 				return err
 			}
 
@@ -171,18 +298,52 @@ func Cmd() *cobra.Command {
 			return nil
 		}
 
-		if err := utils.DoWithTicker(readData, func() {
+		logger.Info("getting data...")
+
+		readErr := utils.DoWithTicker(readData, func() {
 			logger.Info("still getting data...")
-		}); err != nil {
+		})
+
+		logger.Debug("closing file...")
+
+		if err := s.close(); err != nil {
 			return err
 		}
-		<-writeDoneChan
+
+		logger.Debug("file closed")
+
+		if readErr != nil {
+			return readErr
+		}
+
+		logger.Debug("removing checkpoint...")
+
+		if err := removeCheckpoint(filePath); err != nil {
+			logger.Error("could not remove checkpoint", zap.Error(err))
+			return err
+		}
+
 		return nil
 	}
 
 	return &cmd
 }
 
+func parsePrefixes(prefixes []string) (ttl.Prefixes, error) {
+	m := ttl.Prefixes{}
+
+	for _, p := range prefixes {
+		parts := strings.SplitN(p, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("prefix must be name=iri: %s", p)
+		}
+
+		m[strings.TrimSuffix(parts[0], ":")] = strings.Trim(parts[1], "<>")
+	}
+
+	return m, nil
+}
+
 func openExportFile(path string) (*os.File, error) {
 	_, err := os.Stat(path)
 
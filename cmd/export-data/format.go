@@ -0,0 +1,112 @@
+package exportdata
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mick-roper/rdfox-cli/ntriples"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+// rowWriter writes pages of ?s ?p ?o rows read from a cursor to the export file.
+type rowWriter interface {
+	WriteRows(rows []v6.Row) error
+	Flush() error
+}
+
+var graphFormats = []string{"ttl", "nt", "nq"}
+
+func isGraphFormat(format string) bool {
+	for _, f := range graphFormats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newRowWriter creates a rowWriter for format. When appending is set the output continues an existing
+// file, so any document header is not written again.
+func newRowWriter(format string, dst io.Writer, graph, base string, prefixes ttl.Prefixes, appending bool) (rowWriter, error) {
+	switch format {
+	case "ttl":
+		w := ttl.NewWriter(dst, base, prefixes)
+		if appending {
+			w.SkipHeader()
+		}
+
+		return &turtleRowWriter{w}, nil
+	case "nt":
+		return &lineRowWriter{w: ntriples.NewWriter(dst)}, nil
+	case "nq":
+		return &lineRowWriter{w: ntriples.NewWriter(dst), graph: ttl.IRI(graph)}, nil
+	default:
+		return nil, fmt.Errorf("format %s cannot be used to export a single graph - use one of %v", format, graphFormats)
+	}
+}
+
+// turtleRowWriter groups each page by subject, so it holds one page in memory at a time.
+type turtleRowWriter struct {
+	w *ttl.Writer
+}
+
+func (t *turtleRowWriter) WriteRows(rows []v6.Row) error {
+	triples := make([]ttl.Triple, 0, len(rows))
+
+	for _, row := range rows {
+		triple, err := rowToTriple(row)
+		if err != nil {
+			return err
+		}
+
+		triples = append(triples, triple)
+	}
+
+	return t.w.Write(triples)
+}
+
+func (t *turtleRowWriter) Flush() error {
+	return t.w.WriteHeader()
+}
+
+// lineRowWriter streams each row straight out as an N-Triples or N-Quads statement.
+type lineRowWriter struct {
+	w     *ntriples.Writer
+	graph ttl.Term
+}
+
+func (l *lineRowWriter) WriteRows(rows []v6.Row) error {
+	for _, row := range rows {
+		triple, err := rowToTriple(row)
+		if err != nil {
+			return err
+		}
+
+		if err := l.w.WriteQuad(triple, l.graph); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *lineRowWriter) Flush() error {
+	return l.w.Flush()
+}
+
+// rowToTriple checks that a ?s ?p ?o row read from the cursor is a complete triple.
+func rowToTriple(row v6.Row) (ttl.Triple, error) {
+	if len(row.Terms) != 3 {
+		return ttl.Triple{}, fmt.Errorf("expected 3 columns, got %d", len(row.Terms))
+	}
+
+	for _, t := range row.Terms {
+		if t == nil {
+			return ttl.Triple{}, fmt.Errorf("row has an unbound variable")
+		}
+	}
+
+	return ttl.Triple{Subject: row.Terms[0], Predicate: row.Terms[1], Object: row.Terms[2]}, nil
+}
@@ -0,0 +1,261 @@
+package exportdata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/ttl"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// maxHashPartitions is the number of buckets subjects are hashed into: the first byte of their MD5.
+const maxHashPartitions = 256
+
+// hashPartitions returns a FILTER for each of n partitions that splits subjects by the MD5 of their
+// IRI. Blank nodes cannot be hashed with STR, so they all go to the first partition.
+func hashPartitions(n int) ([]string, error) {
+	if n < 1 || n > maxHashPartitions {
+		return nil, fmt.Errorf("partitions must be between 1 and %d", maxHashPartitions)
+	}
+
+	buckets := make([][]string, n)
+	for i := 0; i < maxHashPartitions; i++ {
+		buckets[i%n] = append(buckets[i%n], fmt.Sprintf(`"%02x"`, i))
+	}
+
+	filters := make([]string, n)
+	for i, b := range buckets {
+		cond := fmt.Sprintf("SUBSTR(MD5(STR(?s)), 1, 2) IN (%s)", strings.Join(b, ", "))
+
+		if i == 0 {
+			cond = "isBlank(?s) || " + cond
+		}
+
+		filters[i] = "FILTER(" + cond + ")"
+	}
+
+	return filters, nil
+}
+
+// prefixPartitions returns a FILTER for each subject IRI prefix, plus one for every subject that
+// matches none of them. A subject that matches more than one prefix goes to the first.
+func prefixPartitions(prefixes []string) []string {
+	conds := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		conds[i] = "STRSTARTS(STR(?s), " + ttl.Literal{Value: p}.String() + ")"
+	}
+
+	filters := make([]string, 0, len(prefixes)+1)
+	for i := range conds {
+		cond := conds[i]
+
+		if i > 0 {
+			cond += " && !(" + strings.Join(conds[:i], " || ") + ")"
+		}
+
+		filters = append(filters, "FILTER("+cond+")")
+	}
+
+	filters = append(filters, "FILTER(isBlank(?s) || !("+strings.Join(conds, " || ")+"))")
+
+	return filters
+}
+
+func graphQuery(graph, filter string) string {
+	if filter == "" {
+		return fmt.Sprintf("SELECT ?s ?p ?o FROM <%s> WHERE { ?s ?p ?o }", graph)
+	}
+
+	return fmt.Sprintf("SELECT ?s ?p ?o FROM <%s> WHERE { ?s ?p ?o %s }", graph, filter)
+}
+
+// shardPath returns the file a partition is written to when the export is sharded, e.g.
+// export.nt becomes export.3.nt.
+func shardPath(path string, partition int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(path, ext), partition, ext)
+}
+
+// readGraph opens a connection and a cursor for query and hands every page of results to handle. The
+// next page is not fetched until handle returns.
+func readGraph(ctx context.Context, client *v6.Client, datastore, query string, limit int, handle func([]v6.Row) error) error {
+	logger := utils.LoggerFromContext(ctx)
+
+	logger.Debug("creating a connection...")
+
+	conn, err := client.CreateConnection(ctx, datastore)
+	if err != nil {
+		logger.Error("could not create a connection", zap.Error(err))
+		return err
+	}
+
+	defer func() {
+		logger.Debug("deleting the connection...")
+
+		if err := client.DeleteConnection(context.Background(), conn); err != nil {
+			logger.Error("could not delete connection", zap.Error(err))
+		}
+
+		logger.Debug("connection deleted!")
+	}()
+
+	logger.Debug("connection created", zap.String("connection-id", conn.ID))
+	logger.Debug("creating a cursor...", zap.String("query", query))
+
+	cursor, err := client.CreateCursor(ctx, v6.CreateCursorRequest{Connection: conn, Query: query, Limit: limit})
+	if err != nil {
+		logger.Error("could not create a cursor", zap.Error(err))
+		return err
+	}
+
+	defer func() {
+		logger.Debug("deleting cursor...")
+
+		if err := cursor.Close(context.Background()); err != nil {
+			logger.Error("could not close the cursor", zap.Error(err))
+		}
+
+		logger.Debug("cursor deleted!")
+	}()
+
+	logger.Debug("cursor created", zap.String("cursorID", cursor.ID))
+
+	for {
+		rows, err := cursor.NextBatch(ctx)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := handle(rows); err != nil {
+			return err
+		}
+	}
+}
+
+// partitionedExport describes a graph export that is split across several cursors.
+type partitionedExport struct {
+	datastore string
+	graph     string
+	format    string
+	filePath  string
+	base      string
+	prefixes  ttl.Prefixes
+	limit     int
+	filters   []string
+
+	// shards writes each partition to its own file instead of merging them into filePath.
+	shards bool
+}
+
+// exportPartitions reads every partition concurrently, each over its own connection and cursor. If
+// any partition fails the others are cancelled.
+func exportPartitions(ctx context.Context, client *v6.Client, e partitionedExport) error {
+	logger := utils.LoggerFromContext(ctx)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	paths := []string{e.filePath}
+	if e.shards {
+		paths = make([]string, len(e.filters))
+		for i := range paths {
+			paths[i] = shardPath(e.filePath, i)
+		}
+	}
+
+	sinks := make([]*sink, 0, len(paths))
+
+	closeSinks := func() error {
+		var firstErr error
+
+		for _, s := range sinks {
+			if err := s.close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		return firstErr
+	}
+
+	for _, path := range paths {
+		logger.Debug("opening file for export...", zap.String("file", path))
+
+		f, err := openExportFile(path)
+		if err != nil {
+			logger.Error("could not create export file", zap.Error(err))
+			closeSinks()
+			return err
+		}
+
+		writer, err := newRowWriter(e.format, f, e.graph, e.base, e.prefixes, false)
+		if err != nil {
+			logger.Error("could not create writer", zap.Error(err))
+			f.Close()
+			closeSinks()
+			return err
+		}
+
+		sinks = append(sinks, newSink(logger, f, writer, nil, len(e.filters)))
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(e.filters))
+
+	for i, filter := range e.filters {
+		s := sinks[0]
+		if e.shards {
+			s = sinks[i]
+		}
+
+		wg.Add(1)
+
+		go func(i int, filter string, s *sink) {
+			defer wg.Done()
+
+			logger := logger.With(zap.Int("partition", i))
+			ctx := utils.AddLoggerToContext(ctx, logger)
+
+			logger.Info("reading partition from the server...")
+
+			if err := readGraph(ctx, client, e.datastore, graphQuery(e.graph, filter), e.limit, s.send); err != nil {
+				logger.Error("could not read partition", zap.Error(err))
+				errs[i] = err
+				cancel()
+				return
+			}
+
+			logger.Info("partition read complete")
+		}(i, filter, s)
+	}
+
+	read := func() error {
+		wg.Wait()
+		return nil
+	}
+
+	utils.DoWithTicker(read, func() {
+		logger.Info("still getting data...")
+	})
+
+	if err := closeSinks(); err != nil {
+		return err
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
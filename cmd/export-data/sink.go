@@ -0,0 +1,117 @@
+package exportdata
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// sink owns an export file and writes the pages sent to it from a single goroutine, so that one or
+// more cursors can feed the same file without blocking on each other's writes.
+type sink struct {
+	file   *os.File
+	writer rowWriter
+	rows   chan []v6.Row
+	done   chan struct{}
+	err    error
+
+	// progress is updated and saved after every page when the export is checkpointed.
+	progress *checkpoint
+}
+
+// newSink starts writing to file. buffer is the number of pages that can be queued while the
+// previous page is being written.
+func newSink(logger *zap.Logger, file *os.File, writer rowWriter, progress *checkpoint, buffer int) *sink {
+	s := &sink{
+		file:     file,
+		writer:   writer,
+		rows:     make(chan []v6.Row, buffer),
+		done:     make(chan struct{}),
+		progress: progress,
+	}
+
+	go s.run(logger)
+
+	return s
+}
+
+func (s *sink) run(logger *zap.Logger) {
+	defer close(s.done)
+
+	for rows := range s.rows {
+		rows := rows
+
+		writeFile := func() error {
+			return s.write(rows)
+		}
+
+		logger.Info("writing data to file...", zap.String("file", s.file.Name()))
+
+		if err := utils.DoWithTicker(writeFile, func() {
+			logger.Info("still writing file...", zap.String("file", s.file.Name()))
+		}); err != nil {
+			logger.Error("could not write data", zap.Error(err))
+			s.err = err
+			return
+		}
+
+		logger.Info("write complete", zap.String("file", s.file.Name()))
+	}
+
+	if err := s.writer.Flush(); err != nil {
+		logger.Error("could not flush data", zap.Error(err))
+		s.err = err
+	}
+}
+
+func (s *sink) write(rows []v6.Row) error {
+	if err := s.writer.WriteRows(rows); err != nil {
+		return err
+	}
+
+	if s.progress == nil {
+		return nil
+	}
+
+	// flush everything written so far and record it in the checkpoint, so that a resumed export
+	// continues from the end of this page.
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	s.progress.Rows += int64(len(rows))
+	s.progress.Bytes = offset
+
+	return s.progress.save(s.file.Name())
+}
+
+// send queues a page to be written. It fails if the writer has already stopped.
+func (s *sink) send(rows []v6.Row) error {
+	select {
+	case s.rows <- rows:
+		return nil
+	case <-s.done:
+		return errors.New("the writer stopped before all data was read")
+	}
+}
+
+// close waits for every queued page to be written, then closes the file.
+func (s *sink) close() error {
+	close(s.rows)
+	<-s.done
+
+	if err := s.file.Close(); err != nil && s.err == nil {
+		return err
+	}
+
+	return s.err
+}
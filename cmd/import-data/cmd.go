@@ -0,0 +1,200 @@
+package importdata
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var graph string
+	var mode string
+	var contentType string
+
+	cmd.Use = "import-data [files, directories or globs...]"
+	cmd.Short = "import data into the database"
+	cmd.Long = "streams Turtle (.ttl), N-Triples (.nt), N-Quads (.nq), TriG (.trig) and Datalog (.dlog) files into a datastore. Files may be gzip compressed (.gz)."
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to import the data into")
+	cmd.Flags().StringVar(&graph, "graph", "", "the graph that triples without an explicit graph are imported into (defaults to the default graph)")
+	cmd.Flags().StringVar(&mode, "mode", "add", "<add> to add the data, <delete> to delete it")
+	cmd.Flags().StringVar(&contentType, "content-type", "", "overrides the content type derived from each file's extension")
+
+	cmd.Args = cobra.MinimumNArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		var operation v6.ImportOperation
+
+		switch mode {
+		case "add":
+			operation = v6.ImportAdd
+		case "delete":
+			operation = v6.ImportDelete
+		default:
+			return fmt.Errorf("unknown mode: %s", mode)
+		}
+
+		graph = strings.TrimPrefix(graph, "<")
+		graph = strings.TrimSuffix(graph, ">")
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("finding files...")
+
+		files, err := findFiles(args, contentType != "")
+		if err != nil {
+			logger.Error("could not find files", zap.Error(err))
+			return err
+		}
+
+		if len(files) == 0 {
+			return errors.New("no files to import")
+		}
+
+		logger.Debug("found files", zap.Strings("files", files))
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+
+		for i, path := range files {
+			logger := logger.With(zap.String("file", path), zap.Int("index", i+1), zap.Int("count", len(files)))
+
+			logger.Info("importing file...")
+
+			var res *v6.ImportResult
+
+			doImport := func() error {
+				res, err = importFile(cmd, client, path, v6.ImportRequest{
+					Datastore:    datastore,
+					ContentType:  contentType,
+					Operation:    operation,
+					DefaultGraph: graph,
+				})
+
+				return err
+			}
+
+			if err := utils.DoWithTicker(doImport, func() {
+				logger.Info("still importing file...")
+			}); err != nil {
+				logger.Error("could not import file", zap.Error(err))
+				return err
+			}
+
+			if res.Errors > 0 {
+				err := fmt.Errorf("%s: the import reported %d error(s)", path, res.Errors)
+				logger.Error("file imported with errors", zap.Int64("facts-processed", res.FactsProcessed), zap.Int64("facts-changed", res.FactsChanged), zap.Int64("errors", res.Errors), zap.Error(err))
+				return err
+			}
+
+			logger.Info("file imported", zap.Int64("facts-processed", res.FactsProcessed), zap.Int64("facts-changed", res.FactsChanged), zap.Int64("warnings", res.Warnings))
+		}
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func importFile(cmd *cobra.Command, client *v6.Client, path string, req v6.ImportRequest) (*v6.ImportResult, error) {
+	body, mediaType, err := shared.OpenContentFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	if req.ContentType == "" {
+		req.ContentType = mediaType
+	}
+
+	if req.ContentType == "" {
+		return nil, fmt.Errorf("unknown file type: %s", path)
+	}
+
+	req.Body = body
+
+	return client.ImportContent(cmd.Context(), req)
+}
+
+// findFiles expands each arg, which may be a file, a directory or a glob pattern, into a list of
+// files. Directories are walked recursively and only files with a known extension are kept, unless
+// anyExtension is set.
+func findFiles(args []string, anyExtension bool) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match: %s", arg)
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+
+			err = filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+
+				if d.IsDir() {
+					return nil
+				}
+
+				if _, _, ok := shared.MediaTypeForFile(path); ok || anyExtension {
+					add(path)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return files, nil
+}
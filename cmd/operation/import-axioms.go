@@ -3,6 +3,7 @@ package operation
 import (
 	"errors"
 
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
@@ -38,21 +39,33 @@ func importAxiomsCommand() *cobra.Command {
 		ctx := cmd.Context()
 		logger := utils.LoggerFromContext(ctx)
 
-		logger.Debug("getting flags...")
+		logger.Debug("building client...")
 
-		server := cmd.Flags().Lookup("server").Value.String()
-		protocol := cmd.Flags().Lookup("protocol").Value.String()
-		role := cmd.Flags().Lookup("role").Value.String()
-		password := cmd.Flags().Lookup("password").Value.String()
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
 
-		logger.Debug("got flags", zap.String("server", server), zap.String("protocol", protocol), zap.String("role", role), zap.String("password", password))
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
 		logger.Debug("importing axioms...")
 
-		if err := v6.ImportAxioms(ctx, protocol, server, role, password, datastore, srcGraph, dstGraph); err != nil {
+		req := v6.ImportAxiomsRequest{
+			Datastore:        datastore,
+			SourceGraph:      srcGraph,
+			DestinationGraph: dstGraph,
+		}
+
+		res, err := client.ImportAxioms(ctx, req)
+		if err != nil {
 			logger.Error("could not import axioms", zap.Error(err))
 			return err
 		}
 
+		for _, s := range res.Messages {
+			logger.Info("success", zap.String("data", s))
+		}
+
 		logger.Debug("axioms imported")
 
 		return nil
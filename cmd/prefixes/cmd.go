@@ -0,0 +1,18 @@
+package prefixes
+
+import "github.com/spf13/cobra"
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "prefixes"
+	cmd.Short = "manage datastore prefixes"
+	cmd.Long = "lists and changes the prefixes of a datastore, which are used to compact IRIs in query and export output"
+
+	cmd.AddCommand(listPrefixes())
+	cmd.AddCommand(setPrefix())
+	cmd.AddCommand(deletePrefix())
+	cmd.AddCommand(importPrefixes())
+
+	return &cmd
+}
@@ -0,0 +1,145 @@
+package prefixes
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/datalog"
+	"github.com/mick-roper/rdfox-cli/ttl"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func importPrefixes() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var filePath string
+	var replace bool
+
+	cmd.Use = "import"
+	cmd.Short = "sets the prefixes declared in a file"
+	cmd.Long = "sets every prefix declared by the @prefix or PREFIX directives at the start of a file, e.g. a Turtle or Datalog (.dlog) file. Anything after the directives is ignored."
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to change")
+	cmd.Flags().StringVar(&filePath, "file", "", "the file that declares the prefixes")
+	cmd.Flags().BoolVar(&replace, "replace", false, "<true> to also delete the datastore's prefixes that are not in the file")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		if filePath == "" {
+			return errors.New("file is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading prefixes...")
+
+		prefixes, err := readPrefixes(filePath)
+		if err != nil {
+			logger.Error("could not read prefixes", zap.Error(err))
+			return err
+		}
+
+		if len(prefixes) == 0 {
+			return errors.New("the file does not declare any prefixes")
+		}
+
+		logger.Debug("prefixes read", zap.Int("prefixes", len(prefixes)))
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("getting current prefixes...")
+
+		current, err := client.GetPrefixes(ctx, datastore)
+		if err != nil {
+			logger.Error("could not get prefixes", zap.Error(err))
+			return err
+		}
+
+		names := make([]string, 0, len(prefixes))
+		for name := range prefixes {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		set := 0
+
+		for _, name := range names {
+			iri := prefixes[name]
+
+			if current[name] == iri {
+				logger.Debug("prefix is up to date", zap.String("name", name+":"))
+				continue
+			}
+
+			if err := client.SetPrefix(ctx, datastore, name, iri); err != nil {
+				logger.Error("could not set prefix", zap.String("name", name+":"), zap.Error(err))
+				return err
+			}
+
+			set++
+		}
+
+		deleted := 0
+
+		if replace {
+			for name := range current {
+				if _, ok := prefixes[name]; ok {
+					continue
+				}
+
+				if err := client.DeletePrefix(ctx, datastore, name); err != nil {
+					logger.Error("could not delete prefix", zap.String("name", name+":"), zap.Error(err))
+					return err
+				}
+
+				deleted++
+			}
+		}
+
+		logger.Info("prefixes imported", zap.Int("set", set), zap.Int("deleted", deleted), zap.Int("unchanged", len(prefixes)-set))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+// readPrefixes reads the prefixes declared at the start of a Datalog or Turtle file.
+func readPrefixes(path string) (ttl.Prefixes, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Datalog comments start with %, which Turtle does not allow
+	if strings.EqualFold(filepath.Ext(path), ".dlog") {
+		program, err := datalog.Parse(string(b))
+		if err != nil {
+			return nil, err
+		}
+
+		return program.Prefixes, nil
+	}
+
+	return ttl.ReadPrefixes(bytes.NewReader(b))
+}
@@ -0,0 +1,52 @@
+package prefixes
+
+import (
+	"errors"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func listPrefixes() *cobra.Command {
+	var cmd cobra.Command
+	var datastore string
+
+	cmd.Use = "list"
+	cmd.Short = "lists the prefixes of a datastore"
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore whose prefixes are listed")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("getting prefixes...")
+
+		prefixes, err := client.GetPrefixes(ctx, datastore)
+		if err != nil {
+			logger.Error("could not get prefixes", zap.Error(err))
+			return err
+		}
+
+		logger.Info("got prefixes", zap.Any("prefixes", prefixes))
+
+		return nil
+	}
+
+	return &cmd
+}
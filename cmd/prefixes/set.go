@@ -0,0 +1,116 @@
+package prefixes
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func setPrefix() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var name string
+	var iri string
+
+	cmd.Use = "set"
+	cmd.Short = "adds a prefix to a datastore, or changes its IRI"
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to change")
+	cmd.Flags().StringVar(&name, "name", "", "the prefix name, e.g. ex - use an empty name for the default prefix")
+	cmd.Flags().StringVar(&iri, "iri", "", "the namespace IRI")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		if iri == "" {
+			return errors.New("iri is unset")
+		}
+
+		if !cmd.Flags().Changed("name") {
+			return errors.New("name is unset")
+		}
+
+		name = strings.TrimSuffix(name, ":")
+		iri = strings.Trim(iri, "<>")
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+
+		if err := client.SetPrefix(ctx, datastore, name, iri); err != nil {
+			logger.Error("could not set prefix", zap.Error(err))
+			return err
+		}
+
+		logger.Info("prefix set", zap.String("name", name+":"), zap.String("iri", iri))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func deletePrefix() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var name string
+
+	cmd.Use = "delete"
+	cmd.Short = "deletes a prefix from a datastore"
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to change")
+	cmd.Flags().StringVar(&name, "name", "", "the prefix name - use an empty name for the default prefix")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		if !cmd.Flags().Changed("name") {
+			return errors.New("name is unset")
+		}
+
+		name = strings.TrimSuffix(name, ":")
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+
+		if err := client.DeletePrefix(ctx, datastore, name); err != nil {
+			logger.Error("could not delete prefix", zap.Error(err))
+			return err
+		}
+
+		logger.Info("prefix deleted", zap.String("name", name+":"))
+
+		return nil
+	}
+
+	return &cmd
+}
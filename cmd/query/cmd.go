@@ -0,0 +1,115 @@
+package query
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/sparql"
+	"github.com/mick-roper/rdfox-cli/ttl"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var queryText string
+	var queryFile string
+	var format string
+	var output string
+	var datastorePrefixes bool
+
+	cmd.Use = "query"
+	cmd.Short = "run a SPARQL query against a datastore"
+	cmd.Long = "runs a SPARQL SELECT, ASK, CONSTRUCT or DESCRIBE query. The query is read from --query, --query-file or stdin."
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to query")
+	cmd.Flags().StringVar(&queryText, "query", "", "the query to run")
+	cmd.Flags().StringVar(&queryFile, "query-file", "", "a file containing the query to run ('-' for stdin)")
+	cmd.Flags().StringVar(&format, "format", "", "the output format: table, csv, tsv or json for SELECT/ASK; turtle for CONSTRUCT/DESCRIBE (defaults to table or turtle)")
+	cmd.Flags().StringVar(&output, "output", "", "the file to write results to (defaults to stdout)")
+	cmd.Flags().BoolVar(&datastorePrefixes, "datastore-prefixes", true, "<false> to write full IRIs instead of compacting them with the datastore's prefixes in table and turtle output")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading query...")
+
+		query, err := shared.ReadInput(queryText, queryFile)
+		if err != nil {
+			logger.Error("could not read query", zap.Error(err))
+			return err
+		}
+
+		form := sparql.QueryForm(query)
+
+		logger.Debug("query read", zap.String("query", query), zap.String("form", string(form)))
+
+		format, err := shared.ResolveFormat(form, format)
+		if err != nil {
+			logger.Error("invalid format", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+
+		var prefixes ttl.Prefixes
+
+		if datastorePrefixes && (format == shared.FormatTable || format == shared.FormatTurtle) {
+			prefixes = shared.DatastorePrefixes(ctx, client, datastore)
+		}
+
+		logger.Debug("running query...")
+
+		res, err := client.Query(ctx, v6.QueryRequest{Datastore: datastore, Query: query, Accept: shared.ResultMediaType(format)})
+		if err != nil {
+			logger.Error("could not run query", zap.Error(err))
+			return err
+		}
+
+		defer res.Body.Close()
+
+		logger.Debug("query complete", zap.String("content-type", res.ContentType))
+
+		var dst io.Writer = os.Stdout
+
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				logger.Error("could not create output file", zap.Error(err))
+				return err
+			}
+
+			defer f.Close()
+
+			dst = f
+		}
+
+		if err := shared.RenderResults(format, res.Body, dst, prefixes); err != nil {
+			logger.Error("could not write results", zap.Error(err))
+			return err
+		}
+
+		return nil
+	}
+
+	return &cmd
+}
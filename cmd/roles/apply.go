@@ -0,0 +1,222 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/policy"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func applyPolicy() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "apply"
+	cmd.Short = "make the roles on the server match a policy file"
+	cmd.Long = `reads the roles, memberships and privileges from a YAML or JSON policy file, compares them with the
+server, prints the plan and then makes only the changes that are needed. Every role in the policy
+gets exactly the memberships and privileges it lists. Roles that are not in the policy are left
+alone unless --prune is set.
+
+roles:
+  - name: analysts
+    password_env: ANALYSTS_PASSWORD   # only used to create the role
+    member_of: [readers]
+    privileges:
+      - datastore: production         # type defaults to datastore, and resource to * - the
+        access_types: [read]          # datastore and everything in it
+      - type: named-graph
+        datastore: staging
+        resource: http://example.com/graph
+        access_types: [read, write]
+      - specifier: ">roles"           # a resource specifier as RDFox writes it
+        access_types: [read]`
+
+	var filePath string
+	var planOnly bool
+	var prune bool
+
+	cmd.Flags().StringVar(&filePath, "file", "", "the policy file (.yaml, .yml or .json)")
+	cmd.Flags().BoolVar(&planOnly, "plan-only", false, "<true> to print the plan without changing anything")
+	cmd.Flags().BoolVar(&prune, "prune", false, "<true> to delete roles that are not in the policy, apart from the role the CLI connects as")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		if filePath == "" {
+			logger.Error("arg not set", zap.String("arg", "file"))
+			return errors.New("arg not set")
+		}
+
+		logger.Debug("reading policy...")
+
+		p, err := policy.Load(filePath)
+		if err != nil {
+			logger.Error("invalid policy", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("policy read", zap.Int("roles", len(p.Roles)))
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("reading roles from the server...")
+
+		current, err := currentState(ctx, client, p)
+		if err != nil {
+			logger.Error("could not read roles", zap.Error(err))
+			return err
+		}
+
+		self := utils.RootCommandFlags(cmd).Role
+
+		actions := policy.Plan(p, current, prune, self)
+
+		if len(actions) == 0 {
+			logger.Info("the server already matches the policy")
+			return nil
+		}
+
+		for _, a := range actions {
+			logger.Info("plan", zap.String("action", a.String()))
+		}
+
+		logger.Info("plan complete", zap.Int("actions", len(actions)))
+
+		if planOnly {
+			logger.Info("plan only - no changes have been made")
+			return nil
+		}
+
+		// check every password is available before changing anything
+		passwords := map[string]string{}
+
+		for _, a := range actions {
+			if a.Kind != policy.CreateRole {
+				continue
+			}
+
+			if a.PasswordEnv == "" {
+				return fmt.Errorf("role %s does not exist and has no password_env to create it with", a.Role)
+			}
+
+			if passwords[a.Role] = os.Getenv(a.PasswordEnv); passwords[a.Role] == "" {
+				return fmt.Errorf("role %s does not exist and %s is not set", a.Role, a.PasswordEnv)
+			}
+		}
+
+		for i, a := range actions {
+			logger.Debug("applying action...", zap.String("action", a.String()))
+
+			if err := applyAction(ctx, client, a, passwords); err != nil {
+				logger.Error("could not apply action", zap.String("action", a.String()), zap.Int("applied", i), zap.Error(err))
+				return err
+			}
+		}
+
+		logger.Info("policy applied", zap.Int("actions", len(actions)))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+// currentState reads the roles on the server, with the memberships and privileges of the roles in
+// the policy. Roles that are not in the policy are only needed by name, to prune them. A nil policy
+// reads the memberships and privileges of every role.
+func currentState(ctx context.Context, client *v6.Client, p *policy.Policy) (policy.State, error) {
+	names, err := client.GetRoles(ctx)
+	if err != nil {
+		return policy.State{}, err
+	}
+
+	state := policy.State{Roles: map[string]policy.RoleState{}}
+
+	for _, name := range names {
+		state.Roles[strings.TrimSpace(name)] = policy.RoleState{}
+	}
+
+	var detailed []string
+
+	if p == nil {
+		detailed = sortedRoles(state)
+	} else {
+		for _, r := range p.Roles {
+			if _, ok := state.Roles[r.Name]; ok {
+				detailed = append(detailed, r.Name)
+			}
+		}
+	}
+
+	for _, name := range detailed {
+		memberships, err := client.GetMemberships(ctx, name)
+		if err != nil {
+			return policy.State{}, err
+		}
+
+		privileges, err := client.ListPrivileges(ctx, name)
+		if err != nil {
+			return policy.State{}, err
+		}
+
+		state.Roles[name] = policy.RoleState{MemberOf: memberships, Privileges: privileges}
+	}
+
+	return state, nil
+}
+
+func applyAction(ctx context.Context, client *v6.Client, a policy.Action, passwords map[string]string) error {
+	switch a.Kind {
+	case policy.CreateRole:
+		return client.CreateRole(ctx, v6.CreateRoleRequest{Name: a.Role, Password: passwords[a.Role]})
+	case policy.AddMembership:
+		return client.AddMembership(ctx, a.Role, a.Membership)
+	case policy.RemoveMembership:
+		return client.RemoveMembership(ctx, a.Role, a.Membership)
+	case policy.Grant, policy.Revoke:
+		resource, err := v6.ParseResourceSpecifier(a.Specifier)
+		if err != nil {
+			return err
+		}
+
+		req := v6.UpdatePrivilegesRequest{Role: a.Role, Resource: resource, AccessTypes: strings.Join(a.AccessTypes, ",")}
+
+		if a.Kind == policy.Grant {
+			return client.GrantPrivileges(ctx, req)
+		}
+
+		return client.RevokePrivileges(ctx, req)
+	case policy.DeleteRole:
+		return client.DeleteRole(ctx, a.Role)
+	default:
+		return fmt.Errorf("unknown action %s", a.Kind)
+	}
+}
+
+func sortedRoles(state policy.State) []string {
+	names := make([]string, 0, len(state.Roles))
+	for name := range state.Roles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
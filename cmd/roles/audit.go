@@ -0,0 +1,177 @@
+package roles
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/policy"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func auditRoles() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "audit"
+	cmd.Short = "report the access every role has"
+	cmd.Long = `reads every role with its memberships and privileges, and reports each access type every role has
+on each resource - either granted to the role itself or inherited from a role it is a member of.
+
+Use the filters to answer questions such as who can write a datastore:
+
+  rdfox-cli roles audit --datastore production --access write`
+
+	var roleToInspect string
+	var access string
+	var resourceType string
+	var datastore string
+	var resource string
+	var directOnly bool
+	var format string
+	var output string
+
+	cmd.Flags().StringVar(&roleToInspect, "role-to-inspect", "", "only report this role")
+	cmd.Flags().StringVar(&access, "access", "", "only report this access type: read, write or grant")
+	cmd.Flags().StringVar(&resourceType, "resource-type", "", fmt.Sprintf("only report access to a resource of this type: one of %v (defaults to datastore when --datastore is set)", v6.ResourceTypes))
+	cmd.Flags().StringVar(&datastore, "datastore", "", "only report access to this datastore, or to a resource in it")
+	cmd.Flags().StringVar(&resource, "resource", "", "only report access to this resource, e.g. a tuple table, data source, graph IRI or role")
+	cmd.Flags().BoolVar(&directOnly, "direct-only", false, "<true> to leave out privileges inherited through memberships")
+	cmd.Flags().StringVar(&format, "format", shared.FormatTable, "the output format: table, csv or json")
+	cmd.Flags().StringVar(&output, "output", "", "the file to write the report to (defaults to stdout)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		switch format {
+		case shared.FormatTable, shared.FormatCSV, shared.FormatJSON:
+		default:
+			return fmt.Errorf("unknown format: %s", format)
+		}
+
+		filter := policy.AuditFilter{Role: roleToInspect, DirectOnly: directOnly}
+
+		if access != "" {
+			switch filter.Access = strings.ToLower(access); filter.Access {
+			case "read", "write", "grant":
+			default:
+				return fmt.Errorf("unknown access type %s - use read, write or grant", access)
+			}
+		}
+
+		if resourceType != "" || datastore != "" || resource != "" {
+			if resourceType == "" {
+				resourceType = string(v6.ResourceDatastore)
+			}
+
+			specifier, err := v6.NewResourceSpecifier(v6.ResourceType(resourceType), datastore, resource)
+			if err != nil {
+				logger.Error("invalid resource", zap.Error(err))
+				return err
+			}
+
+			filter.Resource = &specifier
+		}
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("reading roles from the server...")
+
+		state, err := currentState(ctx, client, nil)
+		if err != nil {
+			logger.Error("could not read roles", zap.Error(err))
+			return err
+		}
+
+		if _, ok := state.Roles[roleToInspect]; roleToInspect != "" && !ok {
+			return fmt.Errorf("role %s does not exist", roleToInspect)
+		}
+
+		logger.Debug("roles read", zap.Int("roles", len(state.Roles)))
+
+		entries := policy.Audit(state, filter)
+
+		var dst io.Writer = os.Stdout
+
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				logger.Error("could not create output file", zap.Error(err))
+				return err
+			}
+
+			defer f.Close()
+
+			dst = f
+		}
+
+		if err := writeAudit(entries, format, dst); err != nil {
+			logger.Error("could not write report", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("report written", zap.Int("entries", len(entries)))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+var auditHeader = []string{"role", "resource", "access", "via"}
+
+func writeAudit(entries []policy.Entry, format string, dst io.Writer) error {
+	switch format {
+	case shared.FormatJSON:
+		if entries == nil {
+			entries = []policy.Entry{}
+		}
+
+		enc := json.NewEncoder(dst)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(entries)
+	case shared.FormatCSV:
+		w := csv.NewWriter(dst)
+
+		w.Write(auditHeader)
+		for _, e := range entries {
+			w.Write([]string{e.Role, e.Resource, e.Access, e.Via})
+		}
+
+		w.Flush()
+
+		return w.Error()
+	default:
+		w := tabwriter.NewWriter(dst, 0, 4, 2, ' ', 0)
+
+		rule := make([]string, len(auditHeader))
+		for i, h := range auditHeader {
+			rule[i] = strings.Repeat("-", len(h))
+		}
+
+		fmt.Fprintln(w, strings.Join(auditHeader, "\t"))
+		fmt.Fprintln(w, strings.Join(rule, "\t"))
+
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Role, e.Resource, e.Access, e.Via)
+		}
+
+		return w.Flush()
+	}
+}
@@ -15,6 +15,11 @@ func Cmd() *cobra.Command {
 	cmd.AddCommand(grantPrivileges())
 	cmd.AddCommand(revokePrivileges())
 	cmd.AddCommand(getInfo())
+	cmd.AddCommand(setPassword())
+	cmd.AddCommand(addMember())
+	cmd.AddCommand(removeMember())
+	cmd.AddCommand(applyPolicy())
+	cmd.AddCommand(auditRoles())
 
 	return &cmd
 }
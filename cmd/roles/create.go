@@ -3,6 +3,7 @@ package roles
 import (
 	"fmt"
 
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
@@ -35,14 +36,18 @@ func createRole() *cobra.Command {
 			return fmt.Errorf("arg not set")
 		}
 
-		logger.Debug("getting root command flags...")
+		logger.Debug("building client...")
 
-		r := utils.RootCommandFlags(cmd)
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
 
-		logger.Debug("got root command flags", zap.Any("flags", r))
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
 		logger.Debug("creating role...")
 
-		if err := v6.CreateRole(ctx, r.Server, r.Protocol, r.Role, r.Password, newRoleName, newRolePassword); err != nil {
+		if err := client.CreateRole(ctx, v6.CreateRoleRequest{Name: newRoleName, Password: newRolePassword}); err != nil {
 			logger.Error("could not create role", zap.Error(err))
 			return err
 		}
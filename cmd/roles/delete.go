@@ -3,8 +3,8 @@ package roles
 import (
 	"errors"
 
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	"github.com/mick-roper/rdfox-cli/console"
-	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -37,14 +37,18 @@ func deleteRole() *cobra.Command {
 		}
 
 		logger.Debug("got confirmation")
-		logger.Debug("getting root command flags...")
+		logger.Debug("building client...")
 
-		r := utils.RootCommandFlags(cmd)
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
 
-		logger.Debug("got root command flags", zap.Any("flags", r))
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
 		logger.Debug("deleting role...")
 
-		if err := v6.DeleteRole(ctx, r.Server, r.Protocol, r.Role, r.Password, roleToDelete); err != nil {
+		if err := client.DeleteRole(ctx, roleToDelete); err != nil {
 			logger.Error("could not delete role", zap.Error(err))
 			return err
 		}
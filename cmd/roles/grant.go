@@ -2,7 +2,9 @@ package roles
 
 import (
 	"errors"
+	"fmt"
 
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
@@ -17,12 +19,14 @@ func grantPrivileges() *cobra.Command {
 
 	var roleToUpdate string
 	var datastore string
+	var resourceType string
 	var resource string
 	var accessTypes string
 
 	cmd.Flags().StringVar(&roleToUpdate, "role-to-update", "", "the name of the role to grant privileges to")
-	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore these privileges apply to")
-	cmd.Flags().StringVar(&resource, "resource", "*", "the resource these privileges apply to")
+	cmd.Flags().StringVar(&resourceType, "resource-type", string(v6.ResourceDatastore), fmt.Sprintf("the type of resource these privileges apply to: one of %v", v6.ResourceTypes))
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore these privileges apply to - not used for all and role resources")
+	cmd.Flags().StringVar(&resource, "resource", "*", "the name of the resource these privileges apply to, e.g. a tuple table, data source, graph IRI or role, or * for all of them")
 	cmd.Flags().StringVar(&accessTypes, "access-types", "", "the access types this role should have")
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
@@ -34,24 +38,35 @@ func grantPrivileges() *cobra.Command {
 			return errors.New("arg not set")
 		}
 
-		if datastore == "" {
-			logger.Error("arg not set", zap.String("arg", "datastore"))
-			return errors.New("arg not set")
-		}
-
 		if accessTypes == "" {
 			logger.Error("arg not set", zap.String("arg", "access-types"))
 			return errors.New("arg not set")
 		}
 
-		logger.Debug("getting root command flags...")
+		specifier, err := v6.NewResourceSpecifier(v6.ResourceType(resourceType), datastore, resource)
+		if err != nil {
+			logger.Error("invalid resource", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("building client...")
 
-		r := utils.RootCommandFlags(cmd)
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
 
-		logger.Debug("got root command flags", zap.Any("flags", r))
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
 		logger.Info("granting privileges...")
 
-		if err := v6.GrantDatastorePrivileges(ctx, r.Server, r.Protocol, r.Role, r.Password, roleToUpdate, datastore, resource, accessTypes); err != nil {
+		req := v6.UpdatePrivilegesRequest{
+			Role:        roleToUpdate,
+			Resource:    specifier,
+			AccessTypes: accessTypes,
+		}
+
+		if err := client.GrantPrivileges(ctx, req); err != nil {
 			logger.Error("could not update role", zap.Error(err))
 			return err
 		}
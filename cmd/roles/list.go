@@ -1,7 +1,7 @@
 package roles
 
 import (
-	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -17,14 +17,18 @@ func listRoles() *cobra.Command {
 		ctx := cmd.Context()
 		logger := utils.LoggerFromContext(ctx)
 
-		logger.Debug("getting root flags...")
+		logger.Debug("building client...")
 
-		r := utils.RootCommandFlags(cmd)
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
 
-		logger.Debug("got root command flags", zap.Any("flags", r))
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
 		logger.Debug("getting roles...")
 
-		roles, err := v6.GetRoles(ctx, r.Server, r.Protocol, r.Role, r.Password)
+		roles, err := client.GetRoles(ctx)
 		if err != nil {
 			logger.Error("could not get roles", zap.Error(err))
 			return err
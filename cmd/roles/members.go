@@ -0,0 +1,116 @@
+package roles
+
+import (
+	"errors"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func addMember() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "add-member"
+	cmd.Short = "make a role a member of another role, so it inherits that role's privileges"
+
+	var roleToUpdate string
+	var member string
+
+	cmd.Flags().StringVar(&roleToUpdate, "role-to-update", "", "the name of the role to add the member to")
+	cmd.Flags().StringVar(&member, "member", "", "the name of the role that becomes a member")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		if err := checkMemberArgs(logger, roleToUpdate, member); err != nil {
+			return err
+		}
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("adding member...")
+
+		if err := client.AddMembership(ctx, member, roleToUpdate); err != nil {
+			logger.Error("could not add member", zap.Error(err))
+			return err
+		}
+
+		logger.Info("member added", zap.String("role", roleToUpdate), zap.String("member", member))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func removeMember() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "remove-member"
+	cmd.Short = "stop a role being a member of another role"
+
+	var roleToUpdate string
+	var member string
+
+	cmd.Flags().StringVar(&roleToUpdate, "role-to-update", "", "the name of the role to remove the member from")
+	cmd.Flags().StringVar(&member, "member", "", "the name of the member role")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		if err := checkMemberArgs(logger, roleToUpdate, member); err != nil {
+			return err
+		}
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("removing member...")
+
+		if err := client.RemoveMembership(ctx, member, roleToUpdate); err != nil {
+			logger.Error("could not remove member", zap.Error(err))
+			return err
+		}
+
+		logger.Info("member removed", zap.String("role", roleToUpdate), zap.String("member", member))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func checkMemberArgs(logger *zap.Logger, roleToUpdate, member string) error {
+	if roleToUpdate == "" {
+		logger.Error("arg not set", zap.String("arg", "role-to-update"))
+		return errors.New("arg not set")
+	}
+
+	if member == "" {
+		logger.Error("arg not set", zap.String("arg", "member"))
+		return errors.New("arg not set")
+	}
+
+	if roleToUpdate == member {
+		return errors.New("a role cannot be a member of itself")
+	}
+
+	return nil
+}
@@ -3,7 +3,7 @@ package roles
 import (
 	"errors"
 
-	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -13,7 +13,7 @@ func getInfo() *cobra.Command {
 	var cmd cobra.Command
 
 	cmd.Use = "info"
-	cmd.Short = "get info about a role"
+	cmd.Short = "get info about a role: its direct and inherited memberships and its privileges"
 
 	var roleToInspect string
 
@@ -28,23 +28,43 @@ func getInfo() *cobra.Command {
 			return errors.New("arg not set")
 		}
 
-		logger.Debug("getting root command flags...")
+		logger.Debug("building client...")
 
-		r := utils.RootCommandFlags(cmd)
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
 
-		logger.Debug("got root command flags", zap.Any("flags", r))
-		logger.Debug("getting privileges...")
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("getting memberships...")
 
-		privileges, err := v6.ListPrivileges(ctx, r.Server, r.Protocol, r.Role, r.Password, roleToInspect)
+		direct, inherited, err := client.GetInheritedMemberships(ctx, roleToInspect)
 		if err != nil {
-			logger.Error("could not list privileges", zap.Error(err))
+			logger.Error("could not get memberships", zap.Error(err))
 			return err
 		}
 
-		logger.Debug("got privileges")
+		logger.Info("got memberships", zap.Strings("direct", direct), zap.Strings("inherited", inherited))
+
+		// a role has its own privileges plus those of every role it is a member of
+		roles := append([]string{roleToInspect}, direct...)
+		roles = append(roles, inherited...)
+
+		for _, role := range roles {
+			logger.Debug("getting privileges...", zap.String("role", role))
+
+			privileges, err := client.ListPrivileges(ctx, role)
+			if err != nil {
+				logger.Error("could not list privileges", zap.Error(err))
+				return err
+			}
+
+			logger.Debug("got privileges")
 
-		for resource, accessTypes := range privileges {
-			logger.Info("got data", zap.String("resource", resource), zap.Any("access-types", accessTypes))
+			for resource, accessTypes := range privileges {
+				logger.Info("got data", zap.String("resource", resource), zap.Any("access-types", accessTypes), zap.String("granted-to", role))
+			}
 		}
 
 		return nil
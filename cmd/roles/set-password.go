@@ -0,0 +1,74 @@
+package roles
+
+import (
+	"errors"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/console"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func setPassword() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "set-password"
+	cmd.Short = "change the password of the role the CLI connects as"
+	cmd.Long = "prompts for the current password and the new password without echoing them, then changes the password of the role set by --role"
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading passwords...")
+
+		current, err := console.SecretPrompt("current password:")
+		if err != nil {
+			logger.Error("could not read password", zap.Error(err))
+			return err
+		}
+
+		password, err := console.SecretPrompt("new password:")
+		if err != nil {
+			logger.Error("could not read password", zap.Error(err))
+			return err
+		}
+
+		if password == "" {
+			return errors.New("the new password is empty")
+		}
+
+		confirm, err := console.SecretPrompt("confirm new password:")
+		if err != nil {
+			logger.Error("could not read password", zap.Error(err))
+			return err
+		}
+
+		if password != confirm {
+			return errors.New("the passwords do not match")
+		}
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("changing password...")
+
+		if err := client.ChangePassword(ctx, current, password); err != nil {
+			logger.Error("could not change password", zap.Error(err))
+			return err
+		}
+
+		logger.Info("password changed - update any config, password file or password command that still uses the old one")
+
+		return nil
+	}
+
+	return &cmd
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,10 +11,19 @@ import (
 
 	"github.com/mick-roper/rdfox-cli/cmd/compact"
 	"github.com/mick-roper/rdfox-cli/cmd/config"
+	"github.com/mick-roper/rdfox-cli/cmd/datastore"
+	"github.com/mick-roper/rdfox-cli/cmd/explain"
 	exportdata "github.com/mick-roper/rdfox-cli/cmd/export-data"
+	importdata "github.com/mick-roper/rdfox-cli/cmd/import-data"
 	"github.com/mick-roper/rdfox-cli/cmd/operation"
+	"github.com/mick-roper/rdfox-cli/cmd/prefixes"
+	"github.com/mick-roper/rdfox-cli/cmd/query"
 	"github.com/mick-roper/rdfox-cli/cmd/roles"
+	"github.com/mick-roper/rdfox-cli/cmd/rules"
+	"github.com/mick-roper/rdfox-cli/cmd/shell"
 	"github.com/mick-roper/rdfox-cli/cmd/stats"
+	"github.com/mick-roper/rdfox-cli/cmd/tx"
+	"github.com/mick-roper/rdfox-cli/cmd/update"
 	"github.com/mick-roper/rdfox-cli/cmd/version"
 	configuration "github.com/mick-roper/rdfox-cli/config"
 	"github.com/mick-roper/rdfox-cli/logging"
@@ -27,11 +37,8 @@ func Execute(currentVersion string) int {
 
 	defer cancel()
 
-	client := http.Client{
-		Timeout: time.Minute * 5,
-	}
-
-	ctx = utils.AddHttpClientToContext(ctx, &client)
+	interrupts := &utils.Interrupts{}
+	ctx = utils.AddInterruptsToContext(ctx, interrupts)
 
 	cmd := newRootCommand(ctx)
 	cmd.AddCommand(version.Cmd(currentVersion))
@@ -39,25 +46,50 @@ func Execute(currentVersion string) int {
 	cmd.AddCommand(config.Cmd())
 	cmd.AddCommand(operation.Cmd())
 	cmd.AddCommand(exportdata.Cmd())
+	cmd.AddCommand(importdata.Cmd())
 	cmd.AddCommand(roles.Cmd())
 	cmd.AddCommand(compact.Cmd())
+	cmd.AddCommand(query.Cmd())
+	cmd.AddCommand(update.Cmd())
+	cmd.AddCommand(datastore.Cmd())
+	cmd.AddCommand(shell.Cmd())
+	cmd.AddCommand(tx.Cmd())
+	cmd.AddCommand(rules.Cmd())
+	cmd.AddCommand(prefixes.Cmd())
+	cmd.AddCommand(explain.Cmd())
+
+	preRun := func(cmd *cobra.Command, _ []string) error {
+		// the logger is needed to report a bad config, so it is built even if the config cannot be applied
+		cfgErr := applyConfig(cmd)
 
-	preRun := func(cmd *cobra.Command, _ []string) {
 		level := cmd.Flags().Lookup("log-level").Value.String()
 		logger := logging.New(level)
 		ctx = utils.AddLoggerToContext(cmd.Context(), logger)
 		cmd.SetContext(ctx)
+
+		if cfgErr != nil {
+			return cfgErr
+		}
+
+		logger.Debug("building http client...")
+
+		client, err := newHttpClient(cmd)
+		if err != nil {
+			logger.Error("could not build http client", zap.Error(err))
+			return err
+		}
+
+		ctx = utils.AddHttpClientToContext(ctx, client)
+		cmd.SetContext(ctx)
+
+		return nil
 	}
 
 	postRun := func(cmd *cobra.Command, _ []string) {
 		utils.LoggerFromContext(cmd.Context()).Sync()
 	}
 
-	cmd.PersistentPreRun = preRun
-	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		preRun(cmd, args)
-		return nil
-	}
+	cmd.PersistentPreRunE = preRun
 
 	cmd.PersistentPostRun = postRun
 	cmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
@@ -65,14 +97,13 @@ func Execute(currentVersion string) int {
 		return nil
 	}
 
+	// the channels are buffered so the command can finish after Execute has stopped waiting for it
 	okChan := make(chan struct{}, 1)
 	errChan := make(chan error, 1)
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	defer close(okChan)
-	defer close(errChan)
-	defer close(sigChan)
+	defer signal.Stop(sigChan)
 
 	go func() {
 		if err := cmd.ExecuteContext(ctx); err != nil {
@@ -83,50 +114,209 @@ func Execute(currentVersion string) int {
 		okChan <- struct{}{}
 	}()
 
-	var exitCode int
+	// after an interrupt the command is given time to clean up, e.g. to roll back a transaction
+	var interrupted bool
+	var deadline <-chan time.Time
 
-	select {
-	case <-okChan:
-		exitCode = 0
-	case <-sigChan:
-		cancel()
-		exitCode = 0
-	case err := <-errChan:
-		utils.LoggerFromContext(ctx).Error("execution failed", zap.Error(err))
-		exitCode = 1
-	}
+	for {
+		select {
+		case <-okChan:
+			if interrupted {
+				return exitInterrupted
+			}
 
-	return exitCode
-}
-
-func newRootCommand(ctx context.Context) *cobra.Command {
-	var (
-		defaultLogLevel = "info"
-		defaultProtocol = "https"
-	)
+			return exitOK
+		case err := <-errChan:
+			if interrupted {
+				return exitInterrupted
+			}
 
-	cfg := configuration.Load(configuration.FileLoader(ctx, configuration.DefaultFilePath()), configuration.FromEnv)
+			utils.LoggerFromContext(ctx).Error("execution failed", zap.Error(err))
+			return exitCode(err)
+		case sig := <-sigChan:
+			if sig == os.Interrupt && !interrupted && interrupts.Interrupt() {
+				continue
+			}
 
-	if s := cfg.LogLevel(); s != "" {
-		defaultLogLevel = s
-	}
+			if interrupted {
+				return exitInterrupted
+			}
 
-	if s := cfg.Protocol(); s != "" {
-		defaultProtocol = s
+			interrupted = true
+			deadline = time.After(shutdownTimeout)
+			cancel()
+		case <-deadline:
+			utils.LoggerFromContext(ctx).Warn("the command did not stop in time")
+			return exitInterrupted
+		}
 	}
+}
 
-	defaultServer := cfg.Server()
-	defaultRole := cfg.Role()
-	defaultPassword := cfg.Password()
+// shutdownTimeout is how long an interrupted command has to stop before Execute gives up on it.
+const shutdownTimeout = 10 * time.Second
 
+func newRootCommand(ctx context.Context) *cobra.Command {
 	var cmd cobra.Command
 	cmd.SetContext(ctx)
 	flags := cmd.PersistentFlags()
-	flags.String("log-level", defaultLogLevel, "the log level used by the CLI")
-	flags.String("role", defaultRole, "the role used to communicate with RDFox")
-	flags.String("password", defaultPassword, "the password used to communicate with RDFox")
-	flags.String("server", defaultServer, "the name of the RDFox server")
-	flags.String("protocol", defaultProtocol, "the protocol to use to communicate with RDFox")
+	flags.String("profile", "", "the config profile to use (defaults to $RDFOX_CLI_PROFILE, then the current profile)")
+	flags.String("log-level", "info", "the log level used by the CLI")
+	flags.String("role", "", "the role used to communicate with RDFox")
+	flags.String("password", "", "the password used to communicate with RDFox")
+	flags.String("password-command", "", "a command that prints the password, e.g. 'pass show rdfox' - used when password is not set")
+	flags.String("password-file", "", "a password file written by 'config encrypt-password' - used when password and password-command are not set")
+	flags.String("server", "", "the name of the RDFox server")
+	flags.String("protocol", "https", "the protocol to use to communicate with RDFox")
+	flags.String("ca-cert", "", "a PEM bundle of CA certificates to trust, in addition to the system ones")
+	flags.String("client-cert", "", "a PEM client certificate for mutual TLS")
+	flags.String("client-key", "", "the PEM private key of the client certificate")
+	flags.String("tls-min-version", "", "the minimum TLS version: 1.0, 1.1, 1.2 or 1.3")
+	flags.String("tls-server-name", "", "the name used to verify the server's certificate, if it differs from the server")
+	flags.Bool("insecure", false, "skip verifying the server's certificate - only use this for testing")
+	flags.Int("retries", 3, "the number of times a request is retried after a transient failure, e.g. a 503 or a dropped connection")
+	flags.Duration("retry-max-wait", 30*time.Second, "the longest time to wait between retries")
 
 	return &cmd
 }
+
+// configFlags maps each root flag that can be set from the config to the setting it is read from.
+var configFlags = map[string]func(configuration.Config) string{
+	"log-level": configuration.Config.LogLevel,
+	"role":      configuration.Config.Role,
+	"password":  configuration.Config.Password,
+	"server":    configuration.Config.Server,
+	"protocol":  configuration.Config.Protocol,
+
+	"password-command": configuration.Config.PasswordCommand,
+	"password-file":    configuration.Config.PasswordFile,
+
+	"ca-cert":         func(c configuration.Config) string { return c.TLS().CACert },
+	"client-cert":     func(c configuration.Config) string { return c.TLS().ClientCert },
+	"client-key":      func(c configuration.Config) string { return c.TLS().ClientKey },
+	"tls-min-version": func(c configuration.Config) string { return c.TLS().MinVersion },
+	"tls-server-name": func(c configuration.Config) string { return c.TLS().ServerName },
+	"insecure":        func(c configuration.Config) string { return c.TLS().Insecure },
+}
+
+// passwordFlags are the ways of giving the password. Only one of them is used, so they are taken
+// from the same place.
+var passwordFlags = []string{"password", "password-command", "password-file"}
+
+func isPasswordFlag(name string) bool {
+	for _, f := range passwordFlags {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyConfig fills in every root flag that was not set on the command line from the environment,
+// then from the selected profile in the config file. A missing config file is only an error if a
+// profile was asked for by name.
+func applyConfig(cmd *cobra.Command) error {
+	flags := cmd.Root().PersistentFlags()
+
+	profile := flags.Lookup("profile").Value.String()
+	if profile == "" {
+		profile = configuration.ProfileFromEnv()
+	}
+
+	fileCfg, err := configuration.FileProfile(cmd.Context(), configuration.DefaultFilePath(), profile)
+	if err != nil {
+		missing := errors.Is(err, os.ErrNotExist) && profile == ""
+
+		if !missing && !skipsProfileCheck(cmd) {
+			return err
+		}
+
+		fileCfg = configuration.Load()
+	}
+
+	cfg := configuration.Load(func() configuration.Config { return fileCfg }, configuration.FromEnv)
+
+	// a password source given on the command line wins over every password source in the config
+	passwordFlagSet := false
+	for _, name := range passwordFlags {
+		passwordFlagSet = passwordFlagSet || flags.Lookup(name).Changed
+	}
+
+	for name, get := range configFlags {
+		f := flags.Lookup(name)
+
+		if passwordFlagSet && isPasswordFlag(name) {
+			continue
+		}
+
+		if v := get(cfg); v != "" && !f.Changed {
+			if err := f.Value.Set(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// skipsProfileCheck reports whether cmd manages the config file itself, and so must run even if the
+// selected profile does not exist yet.
+func skipsProfileCheck(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[config.AnnotationManagesConfig] != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newHttpClient builds the client used to talk to RDFox from the TLS and retry flags.
+func newHttpClient(cmd *cobra.Command) (utils.Client, error) {
+	flags := cmd.Root().PersistentFlags()
+
+	insecure, err := flags.GetBool("insecure")
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := utils.NewTLSConfig(utils.TLSOptions{
+		CACert:     flags.Lookup("ca-cert").Value.String(),
+		ClientCert: flags.Lookup("client-cert").Value.String(),
+		ClientKey:  flags.Lookup("client-key").Value.String(),
+		MinVersion: flags.Lookup("tls-min-version").Value.String(),
+		ServerName: flags.Lookup("tls-server-name").Value.String(),
+		Insecure:   insecure,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if insecure {
+		utils.LoggerFromContext(cmd.Context()).Warn("server certificates will not be verified")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   time.Minute * 5,
+	}
+
+	retries, err := flags.GetInt("retries")
+	if err != nil {
+		return nil, err
+	}
+
+	maxWait, err := flags.GetDuration("retry-max-wait")
+	if err != nil {
+		return nil, err
+	}
+
+	if retries <= 0 {
+		return client, nil
+	}
+
+	return utils.NewRetryClient(client, retries, maxWait), nil
+}
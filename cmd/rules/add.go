@@ -0,0 +1,95 @@
+package rules
+
+import (
+	"errors"
+	"os"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func addRules() *cobra.Command {
+	return changeRulesCmd("add", "adds the rules in a datalog file to a datastore", v6.ImportAdd)
+}
+
+func deleteRules() *cobra.Command {
+	return changeRulesCmd("delete", "deletes the rules in a datalog file from a datastore", v6.ImportDelete)
+}
+
+func changeRulesCmd(use, short string, operation v6.ImportOperation) *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var filePath string
+
+	cmd.Use = use
+	cmd.Short = short
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to change")
+	cmd.Flags().StringVar(&filePath, "file", "", "the datalog file")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		if filePath == "" {
+			return errors.New("file is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading rules...")
+
+		program, err := readProgram(filePath)
+		if err != nil {
+			logger.Error("could not read rules", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("rules read", zap.Int("rules", len(program.Rules)))
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+
+		f, err := os.Open(filePath)
+		if err != nil {
+			logger.Error("could not open file", zap.Error(err))
+			return err
+		}
+
+		defer f.Close()
+
+		res, err := client.ImportContent(ctx, v6.ImportRequest{
+			Datastore:   datastore,
+			ContentType: v6.MediaTypeDatalog,
+			Body:        f,
+			Operation:   operation,
+		})
+		if err != nil {
+			logger.Error("could not change rules", zap.Error(err))
+			return err
+		}
+
+		logger.Info("rules changed", zap.String("operation", string(operation)), zap.Int("rules", len(program.Rules)), zap.Int64("facts-changed", res.FactsChanged), zap.Int64("warnings", res.Warnings))
+
+		if res.Errors > 0 {
+			return errors.New("the server reported errors - check the rules")
+		}
+
+		return nil
+	}
+
+	return &cmd
+}
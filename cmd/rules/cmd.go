@@ -0,0 +1,18 @@
+package rules
+
+import "github.com/spf13/cobra"
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	cmd.Use = "rules"
+	cmd.Short = "manage datalog rules"
+	cmd.Long = "lists and changes the datalog rules of a datastore"
+
+	cmd.AddCommand(listRules())
+	cmd.AddCommand(addRules())
+	cmd.AddCommand(deleteRules())
+	cmd.AddCommand(replaceRules())
+
+	return &cmd
+}
@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func listRules() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var filePath string
+
+	cmd.Use = "list"
+	cmd.Short = "lists the rules of a datastore in datalog"
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore whose rules are listed")
+	cmd.Flags().StringVar(&filePath, "file", "", "write the rules to this file instead of stdout")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("getting rules...")
+
+		res, err := client.ExportContent(ctx, v6.ExportRequest{Datastore: datastore, Accept: v6.MediaTypeDatalog})
+		if err != nil {
+			logger.Error("could not get rules", zap.Error(err))
+			return err
+		}
+
+		defer res.Body.Close()
+
+		var dst io.Writer = os.Stdout
+
+		if filePath != "" {
+			f, err := os.Create(filePath)
+			if err != nil {
+				logger.Error("could not create file", zap.Error(err))
+				return err
+			}
+
+			defer f.Close()
+
+			dst = f
+		}
+
+		if _, err := io.Copy(dst, res.Body); err != nil {
+			logger.Error("could not write rules", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("rules written")
+
+		return nil
+	}
+
+	return &cmd
+}
@@ -0,0 +1,66 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/datalog"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+)
+
+// readProgram parses a datalog file, so that syntax errors are reported before anything is changed.
+func readProgram(path string) (*datalog.Program, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := datalog.Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return program, nil
+}
+
+func currentRules(ctx context.Context, tx *v6.Transaction) (*datalog.Program, error) {
+	res, err := tx.Export(ctx, v6.ExportRequest{Accept: v6.MediaTypeDatalog})
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	program, err := datalog.Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the rules from the server: %w", err)
+	}
+
+	return program, nil
+}
+
+func applyRules(ctx context.Context, tx *v6.Transaction, program *datalog.Program, operation v6.ImportOperation) error {
+	res, err := tx.Import(ctx, v6.ImportRequest{
+		ContentType: v6.MediaTypeDatalog,
+		Body:        strings.NewReader(program.String()),
+		Operation:   operation,
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.Errors > 0 {
+		return errors.New("the server reported errors - check the rules")
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package rules
+
+import (
+	"errors"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/datalog"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func replaceRules() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var filePath string
+	var dryRun bool
+
+	cmd.Use = "replace"
+	cmd.Short = "makes the rules of a datastore match a datalog file"
+	cmd.Long = `compares the rules of a datastore with a datalog file, then deletes the rules that are not in the
+file and adds the rules that are missing, in a single transaction. Rules that are in both are left
+alone, so nothing that depends on them is re-derived. Rules are compared ignoring whitespace,
+comments and the prefixes used to write them.`
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to change")
+	cmd.Flags().StringVar(&filePath, "file", "", "the datalog file with the complete rule set")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "<true> to show the changes without making them")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		if filePath == "" {
+			return errors.New("file is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading rules...")
+
+		desired, err := readProgram(filePath)
+		if err != nil {
+			logger.Error("could not read rules", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("rules read", zap.Int("rules", len(desired.Rules)))
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("creating a connection...")
+
+		conn, err := client.CreateConnection(ctx, datastore)
+		if err != nil {
+			logger.Error("could not create a connection", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			ctx, cancel := shared.CleanupContext(ctx)
+			defer cancel()
+
+			if err := conn.Close(ctx); err != nil {
+				logger.Error("could not delete connection", zap.Error(err))
+			}
+		}()
+
+		logger.Debug("beginning transaction...")
+
+		// the rules are read inside the transaction so that nobody can change them between the diff and
+		// the update
+		tx, err := conn.Begin(ctx, dryRun)
+		if err != nil {
+			logger.Error("could not begin transaction", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			ctx, cancel := shared.CleanupContext(ctx)
+			defer cancel()
+
+			if err := tx.Rollback(ctx); err != nil {
+				logger.Error("could not roll back transaction", zap.Error(err))
+			}
+		}()
+
+		logger.Debug("getting current rules...")
+
+		current, err := currentRules(ctx, tx)
+		if err != nil {
+			logger.Error("could not get current rules", zap.Error(err))
+			return err
+		}
+
+		add, remove := datalog.Diff(current, desired)
+
+		for _, r := range remove {
+			logger.Info("rule will be deleted", zap.String("rule", r.Text))
+		}
+
+		for _, r := range add {
+			logger.Info("rule will be added", zap.String("rule", r.Text))
+		}
+
+		logger.Info("rules compared", zap.Int("current", len(current.Rules)), zap.Int("added", len(add)), zap.Int("deleted", len(remove)))
+
+		if len(add) == 0 && len(remove) == 0 {
+			logger.Info("the rules are already up to date")
+			return nil
+		}
+
+		if dryRun {
+			logger.Info("dry run - no changes have been made")
+			return nil
+		}
+
+		if len(remove) > 0 {
+			logger.Debug("deleting rules...")
+
+			if err := applyRules(ctx, tx, current.WithRules(remove), v6.ImportDelete); err != nil {
+				logger.Error("could not delete rules", zap.Error(err))
+				return err
+			}
+		}
+
+		if len(add) > 0 {
+			logger.Debug("adding rules...")
+
+			if err := applyRules(ctx, tx, desired.WithRules(add), v6.ImportAdd); err != nil {
+				logger.Error("could not add rules", zap.Error(err))
+				return err
+			}
+		}
+
+		logger.Debug("committing transaction...")
+
+		if err := tx.Commit(ctx); err != nil {
+			logger.Error("could not commit transaction", zap.Error(err))
+			return err
+		}
+
+		logger.Info("rules replaced")
+
+		return nil
+	}
+
+	return &cmd
+}
@@ -0,0 +1,18 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+)
+
+// cleanupTimeout limits how long cleaning up waits for the server. It is shorter than the time
+// Execute gives an interrupted command to stop.
+const cleanupTimeout = 5 * time.Second
+
+// CleanupContext returns a context for rolling back transactions and closing connections that is
+// not cancelled with ctx, so that the cleanup still happens when the command is interrupted.
+func CleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(utils.Detach(ctx), cleanupTimeout)
+}
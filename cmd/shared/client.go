@@ -0,0 +1,53 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mick-roper/rdfox-cli/config"
+	"github.com/mick-roper/rdfox-cli/console"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+// NewClient builds an RDFox client from the root command flags and the HTTP client in the command context.
+func NewClient(cmd *cobra.Command) (*v6.Client, error) {
+	r := utils.RootCommandFlags(cmd)
+
+	password, err := resolvePassword(cmd.Context(), r.Password, r.PasswordCommand, r.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := v6.BasicAuth(r.Role, password)
+
+	return v6.NewClient(r.Endpoint(), auth, utils.HttpClientFromContext(cmd.Context()))
+}
+
+// resolvePassword returns the password if it is set, otherwise it runs the password command or
+// decrypts the password file. The passphrase for the file is read from RDFOX_CLI_PASSPHRASE, or
+// prompted for.
+func resolvePassword(ctx context.Context, password, command, file string) (string, error) {
+	switch {
+	case password != "":
+		return password, nil
+	case command != "":
+		return config.PasswordFromCommand(ctx, command)
+	case file != "":
+		passphrase := config.PassphraseFromEnv()
+
+		if passphrase == "" {
+			var err error
+
+			passphrase, err = console.SecretPrompt(fmt.Sprintf("passphrase for %s:", file))
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return config.ReadPasswordFile(file, passphrase)
+	default:
+		return "", nil
+	}
+}
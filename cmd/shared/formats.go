@@ -0,0 +1,69 @@
+package shared
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+)
+
+var extensionMediaTypes = map[string]string{
+	".ttl":  v6.MediaTypeTurtle,
+	".nt":   v6.MediaTypeNTriples,
+	".nq":   v6.MediaTypeNQuads,
+	".trig": v6.MediaTypeTriG,
+	".dlog": v6.MediaTypeDatalog,
+	".dl":   v6.MediaTypeDatalog,
+}
+
+// MediaTypeForFile finds the RDFox media type for a file from its extension, looking through a
+// trailing .gz. It reports whether the file is gzip compressed.
+func MediaTypeForFile(path string) (mediaType string, gzipped bool, ok bool) {
+	name := strings.ToLower(path)
+
+	if strings.HasSuffix(name, ".gz") {
+		gzipped = true
+		name = strings.TrimSuffix(name, ".gz")
+	}
+
+	mediaType, ok = extensionMediaTypes[filepath.Ext(name)]
+
+	return mediaType, gzipped, ok
+}
+
+// OpenContentFile opens a file to import, decompressing it if it is gzipped. The media type is
+// empty if the extension is not known. The caller must close the returned reader.
+func OpenContentFile(path string) (io.ReadCloser, string, error) {
+	mediaType, gzipped, _ := MediaTypeForFile(path)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !gzipped {
+		return f, mediaType, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, "", err
+	}
+
+	return gzipFile{gz, f}, mediaType, nil
+}
+
+// gzipFile closes both the gzip reader and the file under it.
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
@@ -0,0 +1,45 @@
+package shared
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// ReadInput returns text if it is set, otherwise the contents of the file at path. When neither is set,
+// or path is '-', the input is read from stdin.
+func ReadInput(text, path string) (string, error) {
+	if text != "" && path != "" {
+		return "", errors.New("only one of the inline text and the file can be set")
+	}
+
+	if text != "" {
+		return text, nil
+	}
+
+	var r io.Reader = os.Stdin
+
+	if path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+
+		defer f.Close()
+
+		r = f
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		return "", errors.New("input is empty")
+	}
+
+	return s, nil
+}
@@ -0,0 +1,28 @@
+package shared
+
+import (
+	"context"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/ttl"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// DatastorePrefixes gets the prefixes used to compact IRIs in output. Compacting is cosmetic, so if
+// the prefixes cannot be read a warning is logged and nil is returned, and IRIs are written in full.
+func DatastorePrefixes(ctx context.Context, client *v6.Client, datastore string) ttl.Prefixes {
+	logger := utils.LoggerFromContext(ctx)
+
+	logger.Debug("getting datastore prefixes...")
+
+	prefixes, err := client.GetPrefixes(ctx, datastore)
+	if err != nil {
+		logger.Warn("could not get the datastore's prefixes - IRIs will be written in full", zap.Error(err))
+		return nil
+	}
+
+	logger.Debug("got datastore prefixes", zap.Int("prefixes", len(prefixes)))
+
+	return prefixes
+}
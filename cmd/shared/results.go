@@ -0,0 +1,99 @@
+package shared
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mick-roper/rdfox-cli/sparql"
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+const (
+	FormatTable  = "table"
+	FormatCSV    = "csv"
+	FormatTSV    = "tsv"
+	FormatJSON   = "json"
+	FormatTurtle = "turtle"
+)
+
+var resultMediaTypes = map[string]string{
+	FormatTable:  "application/sparql-results+json",
+	FormatJSON:   "application/sparql-results+json",
+	FormatCSV:    "text/csv",
+	FormatTSV:    "text/tab-separated-values",
+	FormatTurtle: "text/turtle",
+}
+
+// ResultMediaType returns the media type to ask RDFox for when results are rendered in format.
+func ResultMediaType(format string) string {
+	return resultMediaTypes[format]
+}
+
+// ResolveFormat checks that format can be used for a query of the given form. An empty format
+// defaults to table for SELECT and ASK, and turtle for CONSTRUCT and DESCRIBE.
+func ResolveFormat(form sparql.Form, format string) (string, error) {
+	if form == sparql.Unknown {
+		return "", errors.New("could not determine the query form - only SELECT, ASK, CONSTRUCT and DESCRIBE queries are supported")
+	}
+
+	if format == "" {
+		if form.ReturnsGraph() {
+			return FormatTurtle, nil
+		}
+
+		return FormatTable, nil
+	}
+
+	if _, ok := resultMediaTypes[format]; !ok {
+		return "", fmt.Errorf("unknown format: %s", format)
+	}
+
+	if form.ReturnsGraph() != (format == FormatTurtle) {
+		return "", fmt.Errorf("format %s cannot be used for %s queries", format, form)
+	}
+
+	return format, nil
+}
+
+// RenderResults writes query results read from src to dst. Tables are rendered from SPARQL JSON
+// results, and Turtle is rewritten to compact IRIs with the prefixes. Every other format is copied as
+// RDFox returned it. prefixes may be nil.
+func RenderResults(format string, src io.Reader, dst io.Writer, prefixes ttl.Prefixes) error {
+	switch {
+	case format == FormatTable:
+		results, err := sparql.ReadResults(src)
+		if err != nil {
+			return err
+		}
+
+		return sparql.WriteTable(results, dst, prefixes)
+	case format == FormatTurtle && len(prefixes) > 0:
+		return compactTurtle(src, dst, prefixes)
+	default:
+		_, err := io.Copy(dst, src)
+		return err
+	}
+}
+
+// compactTurtle rewrites a Turtle document with the given prefixes added to its own. The document is
+// copied unchanged if it uses Turtle that ttl.Read does not support.
+func compactTurtle(src io.Reader, dst io.Writer, prefixes ttl.Prefixes) error {
+	b, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+
+	triples, own, err := ttl.Read(bytes.NewReader(b))
+	if err != nil {
+		_, err := dst.Write(b)
+		return err
+	}
+
+	for name, iri := range prefixes {
+		own[name] = iri
+	}
+
+	return ttl.NewWriter(dst, "", own).Write(triples)
+}
@@ -0,0 +1,195 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	"github.com/mick-roper/rdfox-cli/sparql"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	prompt             = "rdfox> "
+	transactionPrompt  = "rdfox*> "
+	continuationPrompt = "   ...> "
+)
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var historyPath string
+
+	cmd.Use = "shell"
+	cmd.Short = "an interactive SPARQL shell"
+	cmd.Long = "opens a connection to a datastore and runs SPARQL queries and updates as they are typed. " +
+		"Statements can span several lines. Queries end with ';', and updates end with a blank line or \\g. " +
+		"Type \\help for the meta-commands."
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to connect to")
+	cmd.Flags().StringVar(&historyPath, "history", defaultHistoryPath(), "the file the shell history is kept in")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("creating a connection...")
+
+		conn, err := client.CreateConnection(ctx, datastore)
+		if err != nil {
+			logger.Error("could not create a connection", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("connection created", zap.String("connection-id", conn.ID))
+
+		s := newSession(ctx, client, conn, os.Stdout)
+
+		defer s.close()
+
+		line := liner.NewLiner()
+		defer line.Close()
+
+		line.SetCtrlCAborts(true)
+		line.SetMultiLineMode(true)
+
+		if f, err := os.Open(historyPath); err == nil {
+			line.ReadHistory(f)
+			f.Close()
+		}
+
+		defer func() {
+			logger.Debug("saving history...", zap.String("path", historyPath))
+
+			f, err := os.OpenFile(historyPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				logger.Error("could not save history", zap.Error(err))
+				return
+			}
+
+			defer f.Close()
+
+			if _, err := line.WriteHistory(f); err != nil {
+				logger.Error("could not save history", zap.Error(err))
+			}
+		}()
+
+		fmt.Fprintf(os.Stdout, "connected to %s on %s - type \\help for help, \\q to quit\n", datastore, client.Endpoint())
+
+		var statement strings.Builder
+
+		for {
+			// the shell has been told to stop, e.g. by SIGTERM
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			p := prompt
+			if s.inTransaction {
+				p = transactionPrompt
+			}
+
+			if statement.Len() > 0 {
+				p = continuationPrompt
+			}
+
+			text, err := line.Prompt(p)
+			if err == liner.ErrPromptAborted {
+				statement.Reset()
+				continue
+			}
+
+			if err == io.EOF {
+				fmt.Fprintln(os.Stdout)
+				return nil
+			}
+
+			if err != nil {
+				logger.Error("could not read input", zap.Error(err))
+				return err
+			}
+
+			trimmed := strings.TrimSpace(text)
+
+			if statement.Len() == 0 && strings.HasPrefix(trimmed, `\`) && trimmed != `\g` {
+				line.AppendHistory(trimmed)
+
+				var quit bool
+
+				err := s.interruptible(func() (err error) {
+					quit, err = s.meta(trimmed)
+					return err
+				})
+				if err != nil {
+					fmt.Fprintln(os.Stdout, "error:", err)
+				}
+
+				if quit {
+					return nil
+				}
+
+				continue
+			}
+
+			if statement.Len() == 0 && (trimmed == "" || trimmed == `\g`) {
+				continue
+			}
+
+			// a blank line or \g sends the statement, and a query also ends with ';'
+			send := trimmed == ""
+
+			if strings.HasSuffix(trimmed, `\g`) {
+				text = strings.TrimSuffix(strings.TrimRight(text, " \t"), `\g`)
+				send = true
+			}
+
+			statement.WriteString(text)
+			statement.WriteString("\n")
+
+			if !send && !sparql.Terminated(statement.String()) {
+				continue
+			}
+
+			text = strings.TrimSpace(statement.String())
+			statement.Reset()
+
+			line.AppendHistory(strings.Join(strings.Fields(text), " "))
+
+			if err := s.interruptible(func() error { return s.run(strings.TrimSuffix(text, ";")) }); err != nil {
+				fmt.Fprintln(os.Stdout, "error:", err)
+			}
+		}
+	}
+
+	return &cmd
+}
+
+func defaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".rdfox-cli_history"
+	}
+
+	return filepath.Join(home, ".rdfox-cli_history")
+}
@@ -0,0 +1,317 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/sparql"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+const help = `statements can span several lines. SELECT, ASK, CONSTRUCT and DESCRIBE queries print their
+results and end with ';'. Anything else is run as a SPARQL update, which may join several operations
+with ';', so it ends with a blank line or \g. Ctrl-C cancels the running statement.
+
+  \prefix [name iri]    list the prefixes, or add one to every statement
+  \graph [iri]          query a named graph as the default graph (no iri to reset)
+  \format [format]      show or set the output format: table, csv, tsv or json
+  \timing [on|off]      show how long each statement takes
+  \explain <query>      explain how RDFox evaluates a query
+  \begin [read-only]    begin a transaction
+  \commit               commit the transaction
+  \rollback             roll the transaction back
+  \help                 show this help
+  \q                    quit
+`
+
+// session holds the connection and the settings changed by meta-commands.
+type session struct {
+	ctx    context.Context
+	client *v6.Client
+	conn   *v6.Connection
+	out    io.Writer
+
+	prefixes      map[string]string
+	graph         string
+	format        string
+	timing        bool
+	inTransaction bool
+}
+
+func newSession(ctx context.Context, client *v6.Client, conn *v6.Connection, out io.Writer) *session {
+	return &session{ctx: ctx, client: client, conn: conn, out: out, prefixes: map[string]string{}}
+}
+
+// close rolls back any open transaction and closes the connection. It still runs after the shell
+// has been interrupted.
+func (s *session) close() {
+	logger := utils.LoggerFromContext(s.ctx)
+
+	ctx, cancel := shared.CleanupContext(s.ctx)
+	defer cancel()
+
+	if s.inTransaction {
+		fmt.Fprintln(s.out, "rolling back the open transaction")
+
+		if err := s.client.RollbackTransaction(ctx, s.conn); err != nil {
+			logger.Error("could not roll back the transaction", zap.Error(err))
+		}
+	}
+
+	logger.Debug("deleting the connection...")
+
+	if err := s.client.DeleteConnection(ctx, s.conn); err != nil {
+		logger.Error("could not delete connection", zap.Error(err))
+	}
+}
+
+// interruptible runs fn with the session's context cancelled by an interrupt, so that Ctrl-C stops
+// the statement and returns to the prompt instead of quitting the shell.
+func (s *session) interruptible(fn func() error) error {
+	parent := s.ctx
+	defer func() { s.ctx = parent }()
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	interrupts := utils.InterruptsFromContext(parent)
+	interrupts.Handle(cancel)
+	defer interrupts.Handle(nil)
+
+	s.ctx = ctx
+
+	err := fn()
+
+	if errors.Is(err, context.Canceled) && ctx.Err() != nil && parent.Err() == nil {
+		return errCancelled
+	}
+
+	return err
+}
+
+var errCancelled = errors.New("cancelled")
+
+// meta runs a meta-command. It returns true if the shell should exit.
+func (s *session) meta(line string) (bool, error) {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+
+	switch name {
+	case `\q`, `\quit`:
+		return true, nil
+	case `\help`, `\?`:
+		fmt.Fprint(s.out, help)
+	case `\prefix`:
+		return false, s.prefix(args)
+	case `\graph`:
+		if len(args) > 1 {
+			return false, errors.New(`usage: \graph [iri]`)
+		}
+
+		s.graph = ""
+		if len(args) == 1 {
+			s.graph = strings.Trim(args[0], "<>")
+		}
+
+		fmt.Fprintf(s.out, "default graph: %s\n", valueOr(s.graph, "the datastore default"))
+	case `\format`:
+		if len(args) > 1 {
+			return false, errors.New(`usage: \format [table|csv|tsv|json]`)
+		}
+
+		if len(args) == 1 {
+			if _, err := shared.ResolveFormat(sparql.Select, args[0]); err != nil {
+				return false, err
+			}
+
+			s.format = args[0]
+		}
+
+		fmt.Fprintf(s.out, "format: %s\n", valueOr(s.format, shared.FormatTable))
+	case `\timing`:
+		switch {
+		case len(args) == 0:
+			s.timing = !s.timing
+		case args[0] == "on":
+			s.timing = true
+		case args[0] == "off":
+			s.timing = false
+		default:
+			return false, errors.New(`usage: \timing [on|off]`)
+		}
+
+		fmt.Fprintf(s.out, "timing: %t\n", s.timing)
+	case `\explain`:
+		query := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, name)), ";")
+		if query == "" {
+			return false, errors.New(`usage: \explain <query>`)
+		}
+
+		return false, s.explain(query)
+	case `\begin`:
+		if s.inTransaction {
+			return false, errors.New("a transaction is already open")
+		}
+
+		readOnly := len(args) == 1 && args[0] == "read-only"
+
+		if err := s.client.BeginTransaction(s.ctx, s.conn, readOnly); err != nil {
+			return false, err
+		}
+
+		s.inTransaction = true
+		fmt.Fprintln(s.out, "transaction started")
+	case `\commit`, `\rollback`:
+		if !s.inTransaction {
+			return false, errors.New("there is no open transaction")
+		}
+
+		end := s.client.CommitTransaction
+		if name == `\rollback` {
+			end = s.client.RollbackTransaction
+		}
+
+		if err := end(s.ctx, s.conn); err != nil {
+			return false, err
+		}
+
+		s.inTransaction = false
+		fmt.Fprintln(s.out, strings.TrimPrefix(name, `\`)+" complete")
+	default:
+		return false, fmt.Errorf(`unknown command %s - type \help for help`, name)
+	}
+
+	return false, nil
+}
+
+func (s *session) prefix(args []string) error {
+	switch len(args) {
+	case 0:
+		names := make([]string, 0, len(s.prefixes))
+		for name := range s.prefixes {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(s.out, "%s: <%s>\n", name, s.prefixes[name])
+		}
+	case 2:
+		s.prefixes[strings.TrimSuffix(args[0], ":")] = strings.Trim(args[1], "<>")
+	default:
+		return errors.New(`usage: \prefix [name iri]`)
+	}
+
+	return nil
+}
+
+// withPrologue prepends the session's prefixes to a statement.
+func (s *session) withPrologue(statement string) string {
+	if len(s.prefixes) == 0 {
+		return statement
+	}
+
+	names := make([]string, 0, len(s.prefixes))
+	for name := range s.prefixes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&sb, "PREFIX %s: <%s>\n", name, s.prefixes[name])
+	}
+
+	sb.WriteString(statement)
+
+	return sb.String()
+}
+
+// run evaluates a statement on the session's connection: queries print their results, anything
+// else is sent as an update.
+func (s *session) run(statement string) error {
+	statement = s.withPrologue(statement)
+	start := time.Now()
+
+	form := sparql.QueryForm(statement)
+
+	if form == sparql.Unknown {
+		res, err := s.client.Update(s.ctx, v6.UpdateRequest{Datastore: s.conn.Datastore, Update: statement, Connection: s.conn})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(s.out, "%d fact(s) added, %d fact(s) deleted\n", res.FactsAdded, res.FactsDeleted)
+	} else {
+		// the output format only applies to SELECT and ASK - graphs are always printed as Turtle
+		format := s.format
+		if form.ReturnsGraph() {
+			format = ""
+		}
+
+		format, err := shared.ResolveFormat(form, format)
+		if err != nil {
+			return err
+		}
+
+		res, err := s.client.Query(s.ctx, v6.QueryRequest{
+			Datastore:    s.conn.Datastore,
+			Query:        statement,
+			Accept:       shared.ResultMediaType(format),
+			DefaultGraph: s.graph,
+			Connection:   s.conn,
+		})
+		if err != nil {
+			return err
+		}
+
+		defer res.Body.Close()
+
+		if err := shared.RenderResults(format, res.Body, s.out, s.prefixes); err != nil {
+			return err
+		}
+	}
+
+	if s.timing {
+		fmt.Fprintf(s.out, "time: %s\n", time.Since(start).Round(time.Microsecond))
+	}
+
+	return nil
+}
+
+func (s *session) explain(query string) error {
+	res, err := s.client.Query(s.ctx, v6.QueryRequest{
+		Datastore:    s.conn.Datastore,
+		Query:        s.withPrologue(query),
+		DefaultGraph: s.graph,
+		Explain:      true,
+		Connection:   s.conn,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	_, err = io.Copy(s.out, res.Body)
+
+	return err
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+
+	return s
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
 	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"github.com/spf13/cobra"
@@ -34,17 +35,18 @@ func Cmd() *cobra.Command {
 			f = jsonFormatter
 		}
 
-		logger.Debug("getting flags...")
+		logger.Debug("building client...")
 
-		server := cmd.Flags().Lookup("server").Value.String()
-		protocol := cmd.Flags().Lookup("protocol").Value.String()
-		role := cmd.Flags().Lookup("role").Value.String()
-		password := cmd.Flags().Lookup("password").Value.String()
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
 
-		logger.Debug("got flags", zap.String("server", server), zap.String("protocol", protocol), zap.String("role", role), zap.String("password", password))
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
 		logger.Debug("getting stats...")
 
-		stats, err := v6.GetStats(ctx, server, protocol, role, password, datastore)
+		stats, err := client.GetStats(ctx, datastore)
 		if err != nil {
 			logger.Error("could not get stats", zap.Error(err))
 			return err
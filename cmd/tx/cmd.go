@@ -0,0 +1,213 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var scriptPath string
+	var dryRun bool
+
+	cmd.Use = "tx"
+	cmd.Short = "run a script of imports and updates in a single transaction"
+	cmd.Long = `runs every step of a script in one transaction, which is committed only if every step succeeds.
+On the first failure the transaction is rolled back, so the datastore is never left half-updated.
+
+Each line of the script is one step - blank lines and lines starting with # are ignored:
+
+  import <file> [graph]   add a file's content (.ttl, .nt, .nq, .trig, or .dlog rules - optionally .gz)
+  delete <file> [graph]   delete a file's content, e.g. rules that are being replaced
+  update <file>           run a SPARQL update from a file
+  sparql <update>         run a one-line SPARQL update
+
+Files are relative to the script.`
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to change")
+	cmd.Flags().StringVar(&scriptPath, "script", "", "the script to run ('-' for stdin)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "<true> to run every step and then roll the transaction back")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		if scriptPath == "" {
+			return errors.New("script is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading script...")
+
+		script, err := shared.ReadInput("", scriptPath)
+		if err != nil {
+			logger.Error("could not read script", zap.Error(err))
+			return err
+		}
+
+		dir := "."
+		if scriptPath != "-" {
+			dir = filepath.Dir(scriptPath)
+		}
+
+		steps, err := parseScript(script, dir)
+		if err != nil {
+			logger.Error("invalid script", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("script read", zap.Int("steps", len(steps)))
+
+		if len(steps) == 0 {
+			return errors.New("the script has no steps")
+		}
+
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+		logger.Debug("creating a connection...")
+
+		conn, err := client.CreateConnection(ctx, datastore)
+		if err != nil {
+			logger.Error("could not create a connection", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			logger.Debug("deleting the connection...")
+
+			ctx, cancel := shared.CleanupContext(ctx)
+			defer cancel()
+
+			if err := conn.Close(ctx); err != nil {
+				logger.Error("could not delete connection", zap.Error(err))
+			}
+
+			logger.Debug("connection deleted!")
+		}()
+
+		logger.Debug("beginning transaction...")
+
+		tx, err := conn.Begin(ctx, false)
+		if err != nil {
+			logger.Error("could not begin transaction", zap.Error(err))
+			return err
+		}
+
+		committed := false
+
+		defer func() {
+			if committed {
+				return
+			}
+
+			logger.Info("rolling back transaction...")
+
+			ctx, cancel := shared.CleanupContext(ctx)
+			defer cancel()
+
+			if err := tx.Rollback(ctx); err != nil {
+				logger.Error("could not roll back transaction", zap.Error(err))
+				return
+			}
+
+			logger.Info("transaction rolled back - the datastore has not been changed")
+		}()
+
+		for i, s := range steps {
+			logger := logger.With(zap.Int("step", i+1), zap.Int("line", s.line), zap.String("operation", s.op))
+
+			if s.path != "" {
+				logger = logger.With(zap.String("file", s.path))
+			}
+
+			logger.Info("running step...")
+
+			run := func() error {
+				return runStep(ctx, tx, s)
+			}
+
+			if err := utils.DoWithTicker(run, func() {
+				logger.Info("still running step...")
+			}); err != nil {
+				logger.Error("step failed", zap.Error(err))
+				return fmt.Errorf("%s: %w", s, err)
+			}
+
+			logger.Info("step complete")
+		}
+
+		if dryRun {
+			logger.Info("dry run - every step succeeded")
+			return nil
+		}
+
+		logger.Debug("committing transaction...")
+
+		if err := tx.Commit(ctx); err != nil {
+			logger.Error("could not commit transaction", zap.Error(err))
+			return err
+		}
+
+		committed = true
+
+		logger.Info("transaction committed", zap.Int("steps", len(steps)))
+
+		return nil
+	}
+
+	return &cmd
+}
+
+func runStep(ctx context.Context, tx *v6.Transaction, s step) error {
+	logger := utils.LoggerFromContext(ctx)
+
+	switch s.op {
+	case opImport, opDelete:
+		body, mediaType, err := shared.OpenContentFile(s.path)
+		if err != nil {
+			return err
+		}
+
+		defer body.Close()
+
+		res, err := tx.Import(ctx, v6.ImportRequest{ContentType: mediaType, Body: body, Operation: s.importOperation(), DefaultGraph: s.graph})
+		if err != nil {
+			return err
+		}
+
+		logger.Info("content imported", zap.Int64("facts-processed", res.FactsProcessed), zap.Int64("facts-changed", res.FactsChanged), zap.Int64("warnings", res.Warnings))
+
+		if res.Errors > 0 {
+			return fmt.Errorf("the import reported %d error(s)", res.Errors)
+		}
+	default:
+		res, err := tx.Update(ctx, s.update)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("update complete", zap.Int64("facts-added", res.FactsAdded), zap.Int64("facts-deleted", res.FactsDeleted))
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package tx
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+)
+
+const (
+	opImport = "import"
+	opDelete = "delete"
+	opUpdate = "update"
+	opSPARQL = "sparql"
+)
+
+// step is one line of a transaction script.
+type step struct {
+	line int
+	op   string
+
+	// path and graph are set for import and delete
+	path  string
+	graph string
+
+	// update is set for update and sparql
+	update string
+}
+
+func (s step) String() string {
+	if s.path != "" {
+		return fmt.Sprintf("line %d: %s %s", s.line, s.op, s.path)
+	}
+
+	return fmt.Sprintf("line %d: %s", s.line, s.op)
+}
+
+// parseScript reads a transaction script. Files are resolved relative to dir, and every file is
+// checked before anything is sent to the server so that a typo cannot abort the transaction halfway.
+func parseScript(script, dir string) ([]step, error) {
+	var steps []step
+
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		op, rest, _ := strings.Cut(text, " ")
+		rest = strings.TrimSpace(rest)
+		args := strings.Fields(rest)
+
+		s := step{line: line, op: op}
+
+		switch op {
+		case opImport, opDelete:
+			if len(args) < 1 || len(args) > 2 {
+				return nil, fmt.Errorf("line %d: usage: %s <file> [graph]", line, op)
+			}
+
+			s.path = resolvePath(dir, args[0])
+
+			if len(args) == 2 {
+				s.graph = strings.Trim(args[1], "<>")
+			}
+
+			if _, _, ok := shared.MediaTypeForFile(s.path); !ok {
+				return nil, fmt.Errorf("line %d: unknown file type: %s", line, args[0])
+			}
+
+			if _, err := os.Stat(s.path); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		case opUpdate:
+			if len(args) != 1 {
+				return nil, fmt.Errorf("line %d: usage: update <file>", line)
+			}
+
+			s.path = resolvePath(dir, args[0])
+
+			b, err := os.ReadFile(s.path)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			s.update = string(b)
+		case opSPARQL:
+			if rest == "" {
+				return nil, fmt.Errorf("line %d: usage: sparql <update>", line)
+			}
+
+			s.update = rest
+		default:
+			return nil, fmt.Errorf("line %d: unknown operation %s - use import, delete, update or sparql", line, op)
+		}
+
+		steps = append(steps, s)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+func (s step) importOperation() v6.ImportOperation {
+	if s.op == opDelete {
+		return v6.ImportDelete
+	}
+
+	return v6.ImportAdd
+}
@@ -0,0 +1,130 @@
+package update
+
+import (
+	"errors"
+
+	"github.com/mick-roper/rdfox-cli/cmd/shared"
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func Cmd() *cobra.Command {
+	var cmd cobra.Command
+
+	var datastore string
+	var updateText string
+	var updateFile string
+	var dryRun bool
+
+	cmd.Use = "update"
+	cmd.Short = "run a SPARQL update against a datastore"
+	cmd.Long = "runs a SPARQL 1.1 update (INSERT DATA, DELETE WHERE, etc). The update is read from --update, --update-file or stdin."
+
+	cmd.Flags().StringVar(&datastore, "datastore", "", "the datastore to update")
+	cmd.Flags().StringVar(&updateText, "update", "", "the update to run")
+	cmd.Flags().StringVar(&updateFile, "update-file", "", "a file containing the update to run ('-' for stdin)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "<true> to run the update in a transaction that is rolled back")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if datastore == "" {
+			return errors.New("datastore is unset")
+		}
+
+		ctx := cmd.Context()
+		logger := utils.LoggerFromContext(ctx)
+
+		logger.Debug("reading update...")
+
+		update, err := shared.ReadInput(updateText, updateFile)
+		if err != nil {
+			logger.Error("could not read update", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("update read", zap.String("update", update))
+		logger.Debug("building client...")
+
+		client, err := shared.NewClient(cmd)
+		if err != nil {
+			logger.Error("could not build client", zap.Error(err))
+			return err
+		}
+
+		logger.Debug("client built", zap.String("endpoint", client.Endpoint()))
+
+		req := v6.UpdateRequest{Datastore: datastore, Update: update}
+
+		if !dryRun {
+			logger.Debug("running update...")
+
+			res, err := client.Update(ctx, req)
+			if err != nil {
+				logger.Error("could not run update", zap.Error(err))
+				return err
+			}
+
+			logger.Info("update complete", zap.Int64("facts-added", res.FactsAdded), zap.Int64("facts-deleted", res.FactsDeleted))
+
+			return nil
+		}
+
+		logger.Debug("creating a connection...")
+
+		conn, err := client.CreateConnection(ctx, datastore)
+		if err != nil {
+			logger.Error("could not create a connection", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			logger.Debug("deleting the connection...")
+
+			ctx, cancel := shared.CleanupContext(ctx)
+			defer cancel()
+
+			if err := conn.Close(ctx); err != nil {
+				logger.Error("could not delete connection", zap.Error(err))
+			}
+
+			logger.Debug("connection deleted!")
+		}()
+
+		logger.Debug("beginning transaction...")
+
+		tx, err := conn.Begin(ctx, false)
+		if err != nil {
+			logger.Error("could not begin transaction", zap.Error(err))
+			return err
+		}
+
+		defer func() {
+			logger.Debug("rolling back transaction...")
+
+			ctx, cancel := shared.CleanupContext(ctx)
+			defer cancel()
+
+			if err := tx.Rollback(ctx); err != nil {
+				logger.Error("could not roll back transaction", zap.Error(err))
+				return
+			}
+
+			logger.Info("dry run - changes have been rolled back")
+		}()
+
+		logger.Debug("running update...")
+
+		res, err := tx.Update(ctx, req.Update)
+		if err != nil {
+			logger.Error("could not run update", zap.Error(err))
+			return err
+		}
+
+		logger.Info("update complete", zap.Int64("facts-added", res.FactsAdded), zap.Int64("facts-deleted", res.FactsDeleted))
+
+		return nil
+	}
+
+	return &cmd
+}
@@ -9,11 +9,7 @@ func (envConfig) Server() string {
 }
 
 func (envConfig) Protocol() string {
-	if s := os.Getenv("RDFOX_CLI_PROTOCOL"); s != "" {
-		return s
-	}
-
-	return "https"
+	return os.Getenv("RDFOX_CLI_PROTOCOL")
 }
 
 func (envConfig) Role() string {
@@ -25,13 +21,38 @@ func (envConfig) Password() string {
 }
 
 func (envConfig) LogLevel() string {
-	if s := os.Getenv("RDFOX_CLI_LOG_LEVEL"); s != "" {
-		return s
+	return os.Getenv("RDFOX_CLI_LOG_LEVEL")
+}
+
+func (envConfig) PasswordCommand() string {
+	return os.Getenv("RDFOX_CLI_PASSWORD_COMMAND")
+}
+
+func (envConfig) PasswordFile() string {
+	return os.Getenv("RDFOX_CLI_PASSWORD_FILE")
+}
+
+func (envConfig) TLS() TLS {
+	return TLS{
+		CACert:     os.Getenv("RDFOX_CLI_CA_CERT"),
+		ClientCert: os.Getenv("RDFOX_CLI_CLIENT_CERT"),
+		ClientKey:  os.Getenv("RDFOX_CLI_CLIENT_KEY"),
+		MinVersion: os.Getenv("RDFOX_CLI_TLS_MIN_VERSION"),
+		ServerName: os.Getenv("RDFOX_CLI_TLS_SERVER_NAME"),
+		Insecure:   os.Getenv("RDFOX_CLI_INSECURE"),
 	}
+}
 
-	return "info"
+// PassphraseFromEnv returns the passphrase for the password file from RDFOX_CLI_PASSPHRASE, if set.
+func PassphraseFromEnv() string {
+	return os.Getenv("RDFOX_CLI_PASSPHRASE")
 }
 
 func FromEnv() Config {
 	return envConfig{}
 }
+
+// ProfileFromEnv returns the profile named by RDFOX_CLI_PROFILE, if any.
+func ProfileFromEnv() string {
+	return os.Getenv("RDFOX_CLI_PROFILE")
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/mick-roper/rdfox-cli/utils"
@@ -18,8 +20,25 @@ const (
 	keyRole     = "role"
 	keyPassword = "password"
 	keyLogLevel = "log_level"
+
+	keyPasswordCommand = "password_command"
+	keyPasswordFile    = "password_file"
+
+	keyCACert        = "ca_cert"
+	keyClientCert    = "client_cert"
+	keyClientKey     = "client_key"
+	keyTLSMinVersion = "tls_min_version"
+	keyTLSServerName = "tls_server_name"
+	keyInsecure      = "insecure"
+
+	// keyCurrentProfile names the profile used when none is given on the command line or in the environment.
+	keyCurrentProfile = "current_profile"
 )
 
+// DefaultProfile is the name of the settings outside any [profile ...] section. Every named profile
+// inherits them.
+const DefaultProfile = "default"
+
 const separator = "\t"
 
 type fileConfig struct {
@@ -28,6 +47,11 @@ type fileConfig struct {
 	role     string
 	password string
 	logLevel string
+
+	passwordCommand string
+	passwordFile    string
+
+	tls TLS
 }
 
 func (f fileConfig) Server() string {
@@ -50,6 +74,18 @@ func (f fileConfig) LogLevel() string {
 	return f.logLevel
 }
 
+func (f fileConfig) PasswordCommand() string {
+	return f.passwordCommand
+}
+
+func (f fileConfig) PasswordFile() string {
+	return f.passwordFile
+}
+
+func (f fileConfig) TLS() TLS {
+	return f.tls
+}
+
 func DefaultFilePath() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -63,116 +99,162 @@ func DefaultFile(ctx context.Context) (Config, error) {
 	return File(ctx, DefaultFilePath())
 }
 
-func FileLoader(ctx context.Context, path string) loader {
-	return func() Config {
-		cfg, err := File(ctx, path)
-		if err != nil {
-			panic(err)
-		}
+// File loads the current profile from the config file at path.
+func File(ctx context.Context, path string) (Config, error) {
+	return FileProfile(ctx, path, "")
+}
 
-		return cfg
+// FileProfile loads a profile from the config file at path. An empty profile selects the file's
+// current profile, or the default profile if none has been chosen. The error wraps os.ErrNotExist
+// if the file does not exist.
+func FileProfile(ctx context.Context, path, profile string) (Config, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func File(ctx context.Context, path string) (Config, error) {
-	_, err := os.Stat(path)
-	if os.IsNotExist(err) {
-		return nil, errors.New("file does not exist")
+	if profile == "" {
+		profile = data.current
 	}
 
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
+	if profile == "" {
+		profile = DefaultProfile
 	}
 
-	defer file.Close()
+	utils.LoggerFromContext(ctx).Debug("loading profile", zap.String("path", path), zap.String("profile", profile))
 
-	data, err := read(file)
-	if err != nil {
-		return nil, err
+	section, ok := data.sections[profile]
+	if !ok && profile != DefaultProfile {
+		return nil, fmt.Errorf("profile %s does not exist in %s", profile, path)
 	}
 
 	var cfg fileConfig
-	for k, v := range data {
-		switch k {
-		case keyLogLevel:
-			cfg.logLevel = v
-		case keyPassword:
-			cfg.password = v
-		case keyProtocol:
-			cfg.protocol = v
-		case keyRole:
-			cfg.role = v
-		case keyServer:
-			cfg.server = v
+	for _, s := range []map[string]string{data.sections[DefaultProfile], section} {
+		// the password, password command and password file are one setting, so a profile that sets
+		// any of them does not inherit the others from the default profile
+		if s[keyPassword] != "" || s[keyPasswordCommand] != "" || s[keyPasswordFile] != "" {
+			cfg.password, cfg.passwordCommand, cfg.passwordFile = "", "", ""
+		}
+
+		for k, v := range s {
+			switch k {
+			case keyLogLevel:
+				cfg.logLevel = v
+			case keyPassword:
+				cfg.password = v
+			case keyProtocol:
+				cfg.protocol = v
+			case keyRole:
+				cfg.role = v
+			case keyServer:
+				cfg.server = v
+			case keyPasswordCommand:
+				cfg.passwordCommand = v
+			case keyPasswordFile:
+				cfg.passwordFile = v
+			case keyCACert:
+				cfg.tls.CACert = v
+			case keyClientCert:
+				cfg.tls.ClientCert = v
+			case keyClientKey:
+				cfg.tls.ClientKey = v
+			case keyTLSMinVersion:
+				cfg.tls.MinVersion = v
+			case keyTLSServerName:
+				cfg.tls.ServerName = v
+			case keyInsecure:
+				cfg.tls.Insecure = v
+			}
 		}
 	}
 
 	return &cfg, nil
 }
 
-func WriteFile(ctx context.Context, path string, cfg Config, overwrite bool) error {
-	logger := utils.LoggerFromContext(ctx).With(zap.String("path", path))
+// ListProfiles returns the names of the profiles in the config file at path, and the current profile.
+func ListProfiles(path string) ([]string, string, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, "", err
+	}
 
-	var file *os.File
+	current := data.current
+	if current == "" {
+		current = DefaultProfile
+	}
 
-	logger.Debug("getting file stats...")
+	return data.names(), current, nil
+}
 
-	_, err := os.Stat(path)
+// UseProfile makes profile the current profile in the config file at path.
+func UseProfile(ctx context.Context, path, profile string) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("path", path), zap.String("profile", profile))
 
-	if err == nil {
-		if !overwrite {
-			return errors.New("file exists but 'overwrite' is <false> - delete the file manually or set 'overwrite' to <true>")
-		}
+	data, err := readFile(path)
+	if err != nil {
+		return err
+	}
 
-		logger.Debug("file exists - opening the file...")
+	if _, ok := data.sections[profile]; !ok && profile != DefaultProfile {
+		return fmt.Errorf("profile %s does not exist in %s", profile, path)
+	}
 
-		file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-		if err != nil {
-			logger.Error("could not open file", zap.Error(err))
-			return err
-		}
+	data.current = profile
+	if profile == DefaultProfile {
+		data.current = ""
+	}
 
-		logger.Debug("file opened - truncating the file...")
+	logger.Debug("writing current profile...")
 
-		if err := file.Truncate(0); err != nil {
-			logger.Error("could not truncate the file", zap.Error(err))
-			return err
-		}
+	return writeFile(path, data)
+}
 
-		logger.Debug("file truncated")
-	} else {
-		if os.IsNotExist(err) {
-			logger.Debug("file does not exist - creating a new file")
+// WriteFile writes cfg to a profile in the config file at path, keeping every other profile. An
+// empty profile writes the default profile.
+func WriteFile(ctx context.Context, path, profile string, cfg Config, overwrite bool) error {
+	if profile == "" {
+		profile = DefaultProfile
+	}
 
-			file, err = os.Create(path)
-			if err != nil {
-				logger.Error("could not create the file", zap.Error(err))
-				return err
-			}
+	logger := utils.LoggerFromContext(ctx).With(zap.String("path", path), zap.String("profile", profile))
 
-			logger.Debug("file created")
-		} else {
-			logger.Error("could not get file stats", zap.Error(err))
-			return err
-		}
+	logger.Debug("reading existing file...")
 
+	data, err := readFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Debug("file does not exist - creating a new file")
+		data = &fileData{sections: map[string]map[string]string{}}
+	} else if err != nil {
+		logger.Error("could not read the file", zap.Error(err))
+		return err
 	}
 
-	defer file.Close()
-
-	logger.Debug("writing file contents")
+	if len(data.sections[profile]) > 0 && !overwrite {
+		return fmt.Errorf("profile %s exists but 'overwrite' is <false> - set 'overwrite' to <true> to replace it", profile)
+	}
 
-	data := map[string]string{
+	data.sections[profile] = map[string]string{
 		keyServer:   cfg.Server(),
 		keyProtocol: cfg.Protocol(),
 		keyRole:     cfg.Role(),
 		keyPassword: cfg.Password(),
 		keyLogLevel: cfg.LogLevel(),
+
+		keyPasswordCommand: cfg.PasswordCommand(),
+		keyPasswordFile:    cfg.PasswordFile(),
+
+		keyCACert:        cfg.TLS().CACert,
+		keyClientCert:    cfg.TLS().ClientCert,
+		keyClientKey:     cfg.TLS().ClientKey,
+		keyTLSMinVersion: cfg.TLS().MinVersion,
+		keyTLSServerName: cfg.TLS().ServerName,
+		keyInsecure:      cfg.TLS().Insecure,
 	}
 
-	if err := write(file, data); err != nil {
-		logger.Error("coudl not write file data", zap.Error(err))
+	logger.Debug("writing file contents")
+
+	if err := writeFile(path, data); err != nil {
+		logger.Error("could not write file data", zap.Error(err))
 		return err
 	}
 
@@ -181,12 +263,69 @@ func WriteFile(ctx context.Context, path string, cfg Config, overwrite bool) err
 	return nil
 }
 
-func read(file *os.File) (map[string]string, error) {
-	m := map[string]string{}
-	scanner := bufio.NewScanner(file)
+// fileData is the parsed config file. Keys before the first [profile name] line belong to the
+// default profile.
+type fileData struct {
+	current  string
+	sections map[string]map[string]string
+}
+
+func (d *fileData) names() []string {
+	var names []string
+	for name := range d.sections {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return append([]string{DefaultProfile}, names...)
+}
+
+func readFile(path string) (*fileData, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("config file %s does not exist: %w", path, os.ErrNotExist)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return read(file)
+}
+
+func read(r io.Reader) (*fileData, error) {
+	data := &fileData{sections: map[string]map[string]string{DefaultProfile: {}}}
+	section := data.sections[DefaultProfile]
+
+	scanner := bufio.NewScanner(r)
 	scanner.Split(bufio.ScanLines)
 	for scanner.Scan() {
-		s := scanner.Text()
+		s := strings.TrimSpace(scanner.Text())
+
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+			fields := strings.Fields(s[1 : len(s)-1])
+			if len(fields) != 2 || fields[0] != "profile" {
+				return nil, fmt.Errorf("invalid section: %s - expected [profile name]", s)
+			}
+
+			name := fields[1]
+			if data.sections[name] == nil {
+				data.sections[name] = map[string]string{}
+			}
+
+			section = data.sections[name]
+			continue
+		}
+
 		parts := strings.SplitN(s, separator, 2)
 
 		if len(parts) != 2 {
@@ -195,19 +334,61 @@ func read(file *os.File) (map[string]string, error) {
 
 		key := parts[0]
 		value := parts[1]
-		m[key] = value
+
+		if key == keyCurrentProfile {
+			data.current = value
+			continue
+		}
+
+		section[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
 
-	return m, nil
+	return data, nil
 }
 
-func write(file *os.File, pairs map[string]string) error {
-	for k, v := range pairs {
-		s := fmt.Sprintf("%s%s%s\n", k, separator, v)
-		if _, err := file.WriteString(s); err != nil {
-			return err
+func write(w io.Writer, data *fileData) error {
+	var sb strings.Builder
+
+	if data.current != "" {
+		fmt.Fprintf(&sb, "%s%s%s\n", keyCurrentProfile, separator, data.current)
+	}
+
+	for _, name := range data.names() {
+		section := data.sections[name]
+
+		if name != DefaultProfile {
+			fmt.Fprintf(&sb, "\n[profile %s]\n", name)
+		}
+
+		keys := make([]string, 0, len(section))
+		for k := range section {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if section[k] != "" {
+				fmt.Fprintf(&sb, "%s%s%s\n", k, separator, section[k])
+			}
 		}
 	}
 
-	return nil
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func writeFile(path string, data *fileData) error {
+	var sb strings.Builder
+
+	if err := write(&sb, data); err != nil {
+		return err
+	}
+
+	return writePrivate(path, []byte(sb.String()))
 }
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	contents := "current_profile\tstaging\n" +
+		"log_level\tdebug\n" +
+		"server\tlocalhost:12110\n" +
+		"\n" +
+		"[profile staging]\n" +
+		"server\tstaging:12110\n" +
+		"role\tops\n"
+
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		profile    string
+		wantServer string
+		wantRole   string
+		wantErr    bool
+	}{
+		{name: "current profile", profile: "", wantServer: "staging:12110", wantRole: "ops"},
+		{name: "default profile", profile: DefaultProfile, wantServer: "localhost:12110"},
+		{name: "named profile", profile: "staging", wantServer: "staging:12110", wantRole: "ops"},
+		{name: "missing profile", profile: "prod", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := FileProfile(context.Background(), path, tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FileProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if cfg.Server() != tt.wantServer || cfg.Role() != tt.wantRole {
+				t.Errorf("FileProfile() server = %q, role = %q, want %q, %q", cfg.Server(), cfg.Role(), tt.wantServer, tt.wantRole)
+			}
+
+			if cfg.LogLevel() != "debug" {
+				t.Errorf("FileProfile() log level = %q, want the default profile's debug", cfg.LogLevel())
+			}
+		})
+	}
+}
+
+func TestFileProfilePasswordSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	contents := "password\tdefault-secret\n" +
+		"\n" +
+		"[profile vault]\n" +
+		"password_command\tpass show rdfox\n" +
+		"\n" +
+		"[profile other]\n" +
+		"server\tother:12110\n"
+
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		profile     string
+		wantPass    string
+		wantCommand string
+	}{
+		{profile: "vault", wantCommand: "pass show rdfox"},
+		{profile: "other", wantPass: "default-secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			cfg, err := FileProfile(context.Background(), path, tt.profile)
+			if err != nil {
+				t.Fatalf("FileProfile() error = %v", err)
+			}
+
+			if cfg.Password() != tt.wantPass || cfg.PasswordCommand() != tt.wantCommand {
+				t.Errorf("FileProfile() password = %q, password command = %q, want %q, %q", cfg.Password(), cfg.PasswordCommand(), tt.wantPass, tt.wantCommand)
+			}
+		})
+	}
+
+	// a password file from a later loader replaces the password from an earlier one
+	cfg := Load(
+		func() Config { return simpleConfig{password: "secret", server: "a"} },
+		func() Config { return simpleConfig{passwordFile: "/secret.enc"} },
+	)
+
+	if cfg.Password() != "" || cfg.PasswordFile() != "/secret.enc" || cfg.Server() != "a" {
+		t.Errorf("Load() password = %q, password file = %q, server = %q", cfg.Password(), cfg.PasswordFile(), cfg.Server())
+	}
+}
+
+func TestWriteFileKeepsOtherProfiles(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "config")
+
+	if err := WriteFile(ctx, path, "", simpleConfig{server: "localhost"}, false); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := WriteFile(ctx, path, "prod", simpleConfig{server: "prod"}, false); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := WriteFile(ctx, path, "prod", simpleConfig{server: "prod2"}, false); err == nil {
+		t.Error("WriteFile() error = nil, want an error when the profile exists")
+	}
+
+	if err := UseProfile(ctx, path, "prod"); err != nil {
+		t.Fatalf("UseProfile() error = %v", err)
+	}
+
+	profiles, current, err := ListProfiles(path)
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+
+	if strings.Join(profiles, ",") != "default,prod" || current != "prod" {
+		t.Errorf("ListProfiles() = %v, %q", profiles, current)
+	}
+
+	cfg, err := File(ctx, path)
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+
+	if cfg.Server() != "prod" {
+		t.Errorf("File() server = %q, want prod", cfg.Server())
+	}
+}
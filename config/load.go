@@ -6,6 +6,11 @@ type simpleConfig struct {
 	role     string
 	password string
 	logLevel string
+
+	passwordCommand string
+	passwordFile    string
+
+	tls TLS
 }
 
 func (f simpleConfig) Server() string {
@@ -28,6 +33,18 @@ func (f simpleConfig) LogLevel() string {
 	return f.logLevel
 }
 
+func (f simpleConfig) PasswordCommand() string {
+	return f.passwordCommand
+}
+
+func (f simpleConfig) PasswordFile() string {
+	return f.passwordFile
+}
+
+func (f simpleConfig) TLS() TLS {
+	return f.tls
+}
+
 type loader func() Config
 
 func Load(loaders ...loader) Config {
@@ -40,10 +57,6 @@ func Load(loaders ...loader) Config {
 			cfg.logLevel = s
 		}
 
-		if s := x.Password(); s != "" {
-			cfg.password = s
-		}
-
 		if s := x.Protocol(); s != "" {
 			cfg.protocol = s
 		}
@@ -55,6 +68,13 @@ func Load(loaders ...loader) Config {
 		if s := x.Server(); s != "" {
 			cfg.server = s
 		}
+
+		// a loader that sets any way of getting the password replaces all of them
+		if x.Password() != "" || x.PasswordCommand() != "" || x.PasswordFile() != "" {
+			cfg.password, cfg.passwordCommand, cfg.passwordFile = x.Password(), x.PasswordCommand(), x.PasswordFile()
+		}
+
+		cfg.tls = cfg.tls.merge(x.TLS())
 	}
 
 	return &cfg
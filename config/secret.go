@@ -0,0 +1,176 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters used for new password files, as recommended for interactive logins.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// passwordFile is the on-disk form of a password encrypted with a key derived from a passphrase.
+type passwordFile struct {
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptPassword encrypts password with AES-GCM, using a key derived from passphrase with scrypt.
+func EncryptPassword(password, passphrase string) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	f := passwordFile{KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: salt}
+
+	gcm, err := f.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return nil, err
+	}
+
+	f.Ciphertext = gcm.Seal(nil, f.Nonce, []byte(password), nil)
+
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// DecryptPassword reverses EncryptPassword.
+func DecryptPassword(data []byte, passphrase string) (string, error) {
+	var f passwordFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return "", fmt.Errorf("invalid password file: %w", err)
+	}
+
+	if f.KDF != "scrypt" {
+		return "", fmt.Errorf("unsupported key derivation function: %s", f.KDF)
+	}
+
+	gcm, err := f.cipher(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	if len(f.Nonce) != gcm.NonceSize() {
+		return "", errors.New("invalid password file: bad nonce")
+	}
+
+	b, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return "", errors.New("could not decrypt the password - is the passphrase correct?")
+	}
+
+	return string(b), nil
+}
+
+func (f passwordFile) cipher(passphrase string) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), f.Salt, f.N, f.R, f.P, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// WritePasswordFile encrypts password and writes it to path, readable only by the current user.
+func WritePasswordFile(path, password, passphrase string) error {
+	data, err := EncryptPassword(password, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return writePrivate(path, data)
+}
+
+// ReadPasswordFile decrypts the password stored at path.
+func ReadPasswordFile(path, passphrase string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return DecryptPassword(data, passphrase)
+}
+
+// PasswordFromCommand runs command with the shell and returns the first line it writes to stdout,
+// e.g. `pass show rdfox/prod` or `op read op://vault/rdfox/password`.
+func PasswordFromCommand(ctx context.Context, command string) (string, error) {
+	logger := utils.LoggerFromContext(ctx)
+
+	logger.Debug("running password command...")
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		logger.Error("password command failed", zap.Error(err), zap.String("stderr", stderr.String()))
+		return "", fmt.Errorf("password command failed: %w", err)
+	}
+
+	password, _, _ := strings.Cut(stdout.String(), "\n")
+	password = strings.TrimSuffix(password, "\r")
+
+	if password == "" {
+		return "", errors.New("password command did not print a password")
+	}
+
+	logger.Debug("got password from command")
+
+	return password, nil
+}
+
+// writePrivate writes data to path with permissions that only allow the current user to read it,
+// tightening the permissions of an existing file.
+func writePrivate(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
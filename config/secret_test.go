@@ -0,0 +1,53 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPasswordFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+
+	if err := WritePasswordFile(path, "s3cret", "correct horse"); err != nil {
+		t.Fatalf("WritePasswordFile() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("file mode = %v, want 0600", mode)
+	}
+
+	got, err := ReadPasswordFile(path, "correct horse")
+	if err != nil {
+		t.Fatalf("ReadPasswordFile() error = %v", err)
+	}
+
+	if got != "s3cret" {
+		t.Errorf("ReadPasswordFile() = %q, want s3cret", got)
+	}
+
+	if _, err := ReadPasswordFile(path, "wrong"); err == nil {
+		t.Error("ReadPasswordFile() with the wrong passphrase error = nil, want error")
+	}
+}
+
+func TestPasswordFromCommand(t *testing.T) {
+	got, err := PasswordFromCommand(context.Background(), "printf 'hunter2\\nignored\\n'")
+	if err != nil {
+		t.Fatalf("PasswordFromCommand() error = %v", err)
+	}
+
+	if got != "hunter2" {
+		t.Errorf("PasswordFromCommand() = %q, want hunter2", got)
+	}
+
+	if _, err := PasswordFromCommand(context.Background(), "exit 1"); err == nil {
+		t.Error("PasswordFromCommand() error = nil, want error")
+	}
+}
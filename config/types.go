@@ -5,5 +5,54 @@ type Config interface {
 	Protocol() string
 	Role() string
 	Password() string
+
+	// PasswordCommand is a shell command that prints the password, used when Password is empty.
+	PasswordCommand() string
+
+	// PasswordFile is a file holding the password encrypted with a passphrase, used when Password
+	// and PasswordCommand are empty.
+	PasswordFile() string
+
 	LogLevel() string
+
+	// TLS configures connections to servers over https.
+	TLS() TLS
+}
+
+// TLS holds the TLS settings. Every field is optional, and an empty field means the setting is not
+// configured, so that settings from different sources can be layered.
+type TLS struct {
+	// CACert is a PEM bundle of CA certificates trusted in addition to the system pool.
+	CACert string
+
+	// ClientCert and ClientKey are PEM files used for mutual TLS.
+	ClientCert string
+	ClientKey  string
+
+	// MinVersion is the minimum TLS version: 1.0, 1.1, 1.2 or 1.3.
+	MinVersion string
+
+	// ServerName overrides the name used to verify the server's certificate.
+	ServerName string
+
+	// Insecure is "true" to skip verifying the server's certificate.
+	Insecure string
+}
+
+// merge returns t with every setting that is set in other replaced.
+func (t TLS) merge(other TLS) TLS {
+	for _, f := range []struct{ dst, src *string }{
+		{&t.CACert, &other.CACert},
+		{&t.ClientCert, &other.ClientCert},
+		{&t.ClientKey, &other.ClientKey},
+		{&t.MinVersion, &other.MinVersion},
+		{&t.ServerName, &other.ServerName},
+		{&t.Insecure, &other.Insecure},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+
+	return t
 }
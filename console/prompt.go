@@ -5,15 +5,20 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"golang.org/x/term"
 )
 
+// stdin is shared by every prompt, so that answers piped in on consecutive lines are not lost in a
+// reader that has been thrown away.
+var stdin = bufio.NewReader(os.Stdin)
+
 func StringPrompt(label string) string {
 	var s string
-	reader := bufio.NewReader(os.Stdin)
 
 	for {
 		fmt.Println(label)
-		s, _ = reader.ReadString('\n')
+		s, _ = stdin.ReadString('\n')
 		if s != "" {
 			break
 		}
@@ -32,3 +37,29 @@ func BoolPrompt(label string) bool {
 
 	return s == "yes"
 }
+
+// SecretPrompt asks for a value without echoing it to the terminal. If stdin is not a terminal the
+// value is read from the next line of stdin instead.
+func SecretPrompt(label string) (string, error) {
+	fd := int(os.Stdin.Fd())
+
+	if !term.IsTerminal(fd) {
+		s, err := stdin.ReadString('\n')
+		if err != nil && s == "" {
+			return "", err
+		}
+
+		return strings.TrimRight(s, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, label+" ")
+
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
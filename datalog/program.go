@@ -0,0 +1,185 @@
+// Package datalog splits RDFox Datalog documents into prefixes and rules so rule sets can be compared.
+// It does not check that rules are valid - that is left to the server.
+package datalog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+var prefixNamePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_\-.]*)?$`)
+
+// Program is a Datalog document: its prefixes, and its rules and facts in the order they were written.
+type Program struct {
+	Prefixes ttl.Prefixes
+	Rules    []Rule
+}
+
+// Rule is a single rule or fact, without the '.' that ends it.
+type Rule struct {
+	// Text is the rule as written, with comments removed and whitespace collapsed.
+	Text string
+
+	// key is the rule with whitespace normalised and prefixed names expanded, so that the same rule
+	// written against different prefixes compares equal.
+	key string
+}
+
+// Parse reads a Datalog document.
+func Parse(src string) (*Program, error) {
+	statements, err := split(src)
+	if err != nil {
+		return nil, err
+	}
+
+	program := Program{Prefixes: ttl.Prefixes{}}
+
+	var rules [][]token
+
+	for _, s := range statements {
+		switch first := strings.ToLower(s[0].text); first {
+		case "@prefix", "prefix":
+			if len(s) != 3 || !strings.HasSuffix(s[1].text, ":") || s[2].kind != tokenIRI {
+				return nil, fmt.Errorf("line %d: a prefix must be written as @prefix name: <iri> .", s[0].line)
+			}
+
+			program.Prefixes[strings.TrimSuffix(s[1].text, ":")] = strings.Trim(s[2].text, "<>")
+		case "@base", "base":
+			return nil, fmt.Errorf("line %d: base directives are not supported", s[0].line)
+		default:
+			rules = append(rules, s)
+		}
+	}
+
+	for _, s := range rules {
+		program.Rules = append(program.Rules, Rule{Text: text(s), key: program.key(s)})
+	}
+
+	return &program, nil
+}
+
+// String writes the program as a Datalog document, with the prefixes sorted by name.
+func (p *Program) String() string {
+	var sb strings.Builder
+
+	names := make([]string, 0, len(p.Prefixes))
+	for name := range p.Prefixes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString("@prefix " + name + ": <" + p.Prefixes[name] + "> .\n")
+	}
+
+	if len(names) > 0 && len(p.Rules) > 0 {
+		sb.WriteString("\n")
+	}
+
+	for _, r := range p.Rules {
+		sb.WriteString(r.Text + " .\n")
+	}
+
+	return sb.String()
+}
+
+// WithRules returns a program with p's prefixes and the given rules.
+func (p *Program) WithRules(rules []Rule) *Program {
+	return &Program{Prefixes: p.Prefixes, Rules: rules}
+}
+
+// Diff finds the rules in desired that are not in current (add), and the rules in current that are
+// not in desired (remove). Duplicate rules are only reported once.
+func Diff(current, desired *Program) (add, remove []Rule) {
+	have := map[string]bool{}
+	for _, r := range current.Rules {
+		have[r.key] = true
+	}
+
+	want := map[string]bool{}
+	for _, r := range desired.Rules {
+		if !have[r.key] && !want[r.key] {
+			add = append(add, r)
+		}
+
+		want[r.key] = true
+	}
+
+	removed := map[string]bool{}
+	for _, r := range current.Rules {
+		if !want[r.key] && !removed[r.key] {
+			remove = append(remove, r)
+		}
+
+		removed[r.key] = true
+	}
+
+	return add, remove
+}
+
+// key joins the statement's tokens with single spaces, expanding prefixed names and 'a'.
+func (p *Program) key(s []token) string {
+	parts := make([]string, len(s))
+
+	for i, t := range s {
+		parts[i] = t.text
+
+		if t.kind != tokenWord {
+			continue
+		}
+
+		if t.text == "a" {
+			parts[i] = "<" + ttl.RDFType + ">"
+			continue
+		}
+
+		word, datatype := strings.CutPrefix(t.text, "^^")
+
+		if iri, ok := p.expand(word); ok {
+			if datatype {
+				iri = "^^" + iri
+			}
+
+			parts[i] = iri
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// expand writes a prefixed name as a full IRI. It reports false if word is not a prefixed name with
+// a known prefix.
+func (p *Program) expand(word string) (string, bool) {
+	name, local, ok := strings.Cut(word, ":")
+	if !ok || strings.HasPrefix(local, "-") || !prefixNamePattern.MatchString(name) {
+		return "", false
+	}
+
+	ns, ok := p.Prefixes[name]
+	if !ok {
+		return "", false
+	}
+
+	return "<" + ns + local + ">", true
+}
+
+// text writes the statement's tokens as they were written, with a single space wherever the source
+// had whitespace or a comment.
+func text(s []token) string {
+	var sb strings.Builder
+
+	for i, t := range s {
+		if i > 0 && t.spaced {
+			sb.WriteString(" ")
+		}
+
+		sb.WriteString(t.text)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,141 @@
+package datalog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		src          string
+		wantPrefixes map[string]string
+		wantRules    []string
+		wantErr      bool
+	}{
+		{
+			name: "prefixes, rules and comments",
+			src: `@prefix ex: <http://example.com/> .
+PREFIX xsd: <http://www.w3.org/2001/XMLSchema#>
+
+% every person is an agent
+[?x, a, ex:Agent] :-
+    [?x, a, ex:Person] .
+
+[?x, ex:adult, true] :- [?x, ex:age, ?a], FILTER(?a >= "18"^^xsd:integer) .
+ex:a ex:name "a. b" .`,
+			wantPrefixes: map[string]string{"ex": "http://example.com/", "xsd": "http://www.w3.org/2001/XMLSchema#"},
+			wantRules: []string{
+				`[?x, a, ex:Agent] :- [?x, a, ex:Person]`,
+				`[?x, ex:adult, true] :- [?x, ex:age, ?a], FILTER(?a >= "18"^^xsd:integer)`,
+				`ex:a ex:name "a. b"`,
+			},
+		},
+		{
+			name:      "comparisons and dots inside terms",
+			src:       "[?x, <http://ex.com/v1.0#p>, ?y] :- [?x, <http://ex.com/q>, ?y], FILTER(?y < 1.5).",
+			wantRules: []string{"[?x, <http://ex.com/v1.0#p>, ?y] :- [?x, <http://ex.com/q>, ?y], FILTER(?y < 1.5)"},
+		},
+		{
+			name:    "missing final dot",
+			src:     "[?x, a, <http://ex.com/A>] :- [?x, a, <http://ex.com/B>]",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated string",
+			src:     `[?x, <http://ex.com/p>, "oops] :- [?x, a, <http://ex.com/B>] .`,
+			wantErr: true,
+		},
+		{
+			name:    "bad prefix",
+			src:     "@prefix ex <http://example.com/> .",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if tt.wantPrefixes == nil {
+				tt.wantPrefixes = map[string]string{}
+			}
+
+			if !reflect.DeepEqual(map[string]string(got.Prefixes), tt.wantPrefixes) {
+				t.Errorf("Parse() prefixes = %v, want %v", got.Prefixes, tt.wantPrefixes)
+			}
+
+			var rules []string
+			for _, r := range got.Rules {
+				rules = append(rules, r.Text)
+			}
+
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("Parse() rules = %q, want %q", rules, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	current, err := Parse(`@prefix ex: <http://example.com/> .
+[?x, a, ex:A] :- [?x, a, ex:B] .
+[?x, a, ex:C] :- [?x, a, ex:D] .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same first rule, written with a different prefix and layout
+	desired, err := Parse(`@prefix e: <http://example.com/> .
+[?x, <http://www.w3.org/1999/02/22-rdf-syntax-ns#type>, e:A] :-
+    [?x, a, <http://example.com/B>] .
+[?x, a, e:E] :- [?x, a, e:F] .
+[?x, a, e:E] :- [?x, a, e:F] .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	add, remove := Diff(current, desired)
+
+	if len(add) != 1 || add[0].Text != "[?x, a, e:E] :- [?x, a, e:F]" {
+		t.Errorf("Diff() add = %v", add)
+	}
+
+	if len(remove) != 1 || remove[0].Text != "[?x, a, ex:C] :- [?x, a, ex:D]" {
+		t.Errorf("Diff() remove = %v", remove)
+	}
+
+	want := "@prefix e: <http://example.com/> .\n\n[?x, a, e:E] :- [?x, a, e:F] .\n"
+	if got := desired.WithRules(add).String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffIgnoresSpacing(t *testing.T) {
+	current, err := Parse(`@prefix ex: <http://example.com/> .
+ex:A[?x] :- ex:B[?x], ex:C[?x, ?y] .`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, src := range []string{
+		"@prefix ex: <http://example.com/> .\nex:A[?x]:-ex:B[?x],ex:C[?x,?y].",
+		"@prefix ex: <http://example.com/> .\nex:A[ ?x ] :-\n    ex:B[ ?x ] ,\n    ex:C[ ?x , ?y ]\n.",
+	} {
+		desired, err := Parse(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if add, remove := Diff(current, desired); len(add) != 0 || len(remove) != 0 {
+			t.Errorf("Diff() of %q add = %v, remove = %v, want no changes", src, add, remove)
+		}
+	}
+}
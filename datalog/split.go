@@ -0,0 +1,157 @@
+package datalog
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenIRI
+	tokenString
+	tokenPunctuation
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+
+	// spaced is true if the token followed whitespace or a comment
+	spaced bool
+}
+
+// split reads src as a list of statements. A statement ends with a '.' followed by whitespace, a
+// comment or the end of the document, except SPARQL-style PREFIX directives which have no '.'.
+// Comments start with '%' (or '#') and run to the end of the line.
+func split(src string) ([][]token, error) {
+	var statements [][]token
+	var current []token
+
+	line := 1
+	spaced := false
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '\n':
+			line++
+			spaced = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			spaced = true
+			i++
+			continue
+		case c == '%' || c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+			spaced = true
+			continue
+		case c == '.' && endsStatement(src, i):
+			if len(current) == 0 {
+				return nil, fmt.Errorf("line %d: unexpected '.'", line)
+			}
+
+			statements = append(statements, current)
+			current = nil
+			spaced = true
+			i++
+			continue
+		}
+
+		t := token{line: line, spaced: spaced}
+		start := i
+
+		switch {
+		case c == '"' || c == '\'':
+			end, err := stringEnd(src, i)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			t.kind, i = tokenString, end
+		case c == '<' && iriEnd(src, i) > 0:
+			t.kind, i = tokenIRI, iriEnd(src, i)
+		case strings.IndexByte("[](),;<", c) >= 0:
+			t.kind, i = tokenPunctuation, i+1
+		case strings.HasPrefix(src[i:], ":-"):
+			t.kind, i = tokenPunctuation, i+2
+		default:
+			t.kind = tokenWord
+
+			// a local name cannot start with '-', so ':-' is always the rule's arrow
+			for i < len(src) && !isDelimiter(src[i]) && !(src[i] == '.' && endsStatement(src, i)) && !strings.HasPrefix(src[i:], ":-") {
+				i++
+			}
+		}
+
+		t.text = src[start:i]
+		line += strings.Count(t.text, "\n")
+		spaced = false
+
+		current = append(current, t)
+
+		// a SPARQL-style prefix directive ends at its IRI
+		if t.kind == tokenIRI && len(current) == 3 && strings.EqualFold(current[0].text, "prefix") {
+			statements = append(statements, current)
+			current = nil
+		}
+	}
+
+	if len(current) > 0 {
+		return nil, fmt.Errorf("line %d: the last statement does not end with '.'", current[0].line)
+	}
+
+	return statements, nil
+}
+
+func endsStatement(src string, i int) bool {
+	return i+1 == len(src) || strings.IndexByte(" \t\r\n%#", src[i+1]) >= 0
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte(" \t\r\n%\"'[](),;<", c) >= 0
+}
+
+// stringEnd finds the end of the quoted string starting at i, including any language tag.
+func stringEnd(src string, i int) (int, error) {
+	quote := src[i]
+
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '\n':
+			return 0, fmt.Errorf("unterminated string")
+		case quote:
+			return j + 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated string")
+}
+
+// iriEnd finds the end of the IRI starting at i, or returns 0 if the '<' is not the start of an IRI,
+// e.g. because it is a comparison.
+func iriEnd(src string, i int) int {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '>':
+			if j == i+1 {
+				return 0
+			}
+
+			return j + 1
+		case ' ', '\t', '\r', '\n', '<', '"', '{', '}', '|', '^', '`':
+			return 0
+		}
+	}
+
+	return 0
+}
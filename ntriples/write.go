@@ -0,0 +1,50 @@
+package ntriples
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+// Writer writes N-Triples or N-Quads, one statement per line, in the order it is given them. Nothing
+// is buffered beyond a single bufio.Writer, so arbitrarily large graphs can be streamed.
+type Writer struct {
+	dst *bufio.Writer
+}
+
+func NewWriter(dst io.Writer) *Writer {
+	return &Writer{dst: bufio.NewWriter(dst)}
+}
+
+// WriteTriple writes t as an N-Triples statement.
+func (w *Writer) WriteTriple(t ttl.Triple) error {
+	return w.WriteQuad(t, nil)
+}
+
+// WriteQuad writes t as an N-Quads statement in graph. A nil graph writes t in the default graph.
+func (w *Writer) WriteQuad(t ttl.Triple, graph ttl.Term) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	w.dst.WriteString(t.Subject.String())
+	w.dst.WriteByte(' ')
+	w.dst.WriteString(t.Predicate.String())
+	w.dst.WriteByte(' ')
+	w.dst.WriteString(t.Object.String())
+
+	if graph != nil {
+		w.dst.WriteByte(' ')
+		w.dst.WriteString(graph.String())
+	}
+
+	_, err := w.dst.WriteString(" .\n")
+
+	return err
+}
+
+// Flush writes any buffered statements to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.dst.Flush()
+}
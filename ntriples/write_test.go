@@ -0,0 +1,66 @@
+package ntriples
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+func TestWriter(t *testing.T) {
+	const ex = "http://example.com/"
+
+	triples := []ttl.Triple{
+		{Subject: ttl.IRI(ex + "b"), Predicate: ttl.IRI(ex + "p"), Object: ttl.Literal{Value: "say \"hi\"\n", Lang: "en"}},
+		{Subject: ttl.BlankNode("x"), Predicate: ttl.IRI(ex + "p"), Object: ttl.Literal{Value: "1", Datatype: ttl.XSDInteger}},
+	}
+
+	tests := []struct {
+		name  string
+		graph ttl.Term
+		want  string
+	}{
+		{
+			name: "triples",
+			want: `<http://example.com/b> <http://example.com/p> "say \"hi\"\n"@en .
+_:x <http://example.com/p> "1"^^<http://www.w3.org/2001/XMLSchema#integer> .
+`,
+		},
+		{
+			name:  "quads",
+			graph: ttl.IRI(ex + "g"),
+			want: `<http://example.com/b> <http://example.com/p> "say \"hi\"\n"@en <http://example.com/g> .
+_:x <http://example.com/p> "1"^^<http://www.w3.org/2001/XMLSchema#integer> <http://example.com/g> .
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst bytes.Buffer
+			w := NewWriter(&dst)
+
+			for _, triple := range triples {
+				if err := w.WriteQuad(triple, tt.graph); err != nil {
+					t.Fatalf("WriteQuad() error = %v", err)
+				}
+			}
+
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+
+			if got := dst.String(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterRejectsInvalidTriples(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{})
+
+	err := w.WriteTriple(ttl.Triple{Subject: ttl.Literal{Value: "x"}, Predicate: ttl.IRI("http://example.com/p"), Object: ttl.IRI("http://example.com/o")})
+	if err == nil {
+		t.Error("WriteTriple() error = nil, want error")
+	}
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"sort"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+)
+
+// Entry is one access type a role has on a resource.
+type Entry struct {
+	Role     string `json:"role"`
+	Resource string `json:"resource"`
+	Access   string `json:"access"`
+
+	// Via is the role the privilege is granted to - the role itself, or a role it inherits it from
+	// through its memberships.
+	Via string `json:"via"`
+}
+
+// AuditFilter narrows an audit. Every field is optional.
+type AuditFilter struct {
+	Role   string
+	Access string
+
+	// Resource keeps the entries whose resource covers it, i.e. the roles that have access to it.
+	Resource *v6.ResourceSpecifier
+
+	// DirectOnly leaves out privileges inherited through memberships.
+	DirectOnly bool
+}
+
+// Audit lists the access every role in the state has, directly and through its memberships, sorted
+// by role, resource and access type. The state must hold the memberships and privileges of every
+// role, so that inherited privileges can be resolved.
+func Audit(state State, filter AuditFilter) []Entry {
+	var entries []Entry
+
+	for _, role := range sortedKeys(state.Roles) {
+		if filter.Role != "" && role != filter.Role {
+			continue
+		}
+
+		via := []string{role}
+		if !filter.DirectOnly {
+			via = append(via, inherited(state, role)...)
+		}
+
+		for _, from := range via {
+			privileges := state.Roles[from].Privileges
+
+			for _, spec := range sortedKeys(privileges) {
+				if filter.Resource != nil {
+					resource, err := v6.ParseResourceSpecifier(spec)
+					if err != nil || !resource.Covers(*filter.Resource) {
+						continue
+					}
+				}
+
+				// unknown access types are left out rather than failing the whole audit
+				set, _ := accessTypeSet(privileges[spec])
+
+				for _, access := range accessTypeOrder {
+					if !set[access] || (filter.Access != "" && access != filter.Access) {
+						continue
+					}
+
+					entries = append(entries, Entry{Role: role, Resource: spec, Access: access, Via: from})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+
+		return accessTypeIndex(a.Access) < accessTypeIndex(b.Access)
+	})
+
+	return entries
+}
+
+// inherited lists the roles role is a member of, directly or indirectly, in breadth first order.
+func inherited(state State, role string) []string {
+	seen := map[string]bool{role: true}
+	queue := state.Roles[role].MemberOf
+
+	var out []string
+
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		if seen[next] {
+			continue
+		}
+
+		seen[next] = true
+		out = append(out, next)
+		queue = append(queue, state.Roles[next].MemberOf...)
+	}
+
+	return out
+}
+
+func accessTypeIndex(t string) int {
+	for i, o := range accessTypeOrder {
+		if o == t {
+			return i
+		}
+	}
+
+	return len(accessTypeOrder)
+}
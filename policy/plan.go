@@ -0,0 +1,226 @@
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// State is what a server has now, as read with GetRoles, GetMemberships and ListPrivileges.
+type State struct {
+	Roles map[string]RoleState
+}
+
+type RoleState struct {
+	MemberOf []string
+
+	// Privileges maps resource specifiers to access types.
+	Privileges map[string][]string
+}
+
+type ActionKind string
+
+const (
+	CreateRole       ActionKind = "create-role"
+	AddMembership    ActionKind = "add-membership"
+	Grant            ActionKind = "grant"
+	Revoke           ActionKind = "revoke"
+	RemoveMembership ActionKind = "remove-membership"
+	DeleteRole       ActionKind = "delete-role"
+)
+
+// Action is one change to the server.
+type Action struct {
+	Kind ActionKind
+	Role string
+
+	// PasswordEnv is set for CreateRole.
+	PasswordEnv string
+
+	// Membership is the role joined or left, for AddMembership and RemoveMembership.
+	Membership string
+
+	// Specifier and AccessTypes are set for Grant and Revoke.
+	Specifier   string
+	AccessTypes []string
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case CreateRole:
+		return fmt.Sprintf("+ create role %s", a.Role)
+	case AddMembership:
+		return fmt.Sprintf("+ add %s to %s", a.Role, a.Membership)
+	case Grant:
+		return fmt.Sprintf("+ grant %s on %s to %s", strings.Join(a.AccessTypes, ","), a.Specifier, a.Role)
+	case Revoke:
+		return fmt.Sprintf("- revoke %s on %s from %s", strings.Join(a.AccessTypes, ","), a.Specifier, a.Role)
+	case RemoveMembership:
+		return fmt.Sprintf("- remove %s from %s", a.Role, a.Membership)
+	case DeleteRole:
+		return fmt.Sprintf("- delete role %s", a.Role)
+	default:
+		return string(a.Kind)
+	}
+}
+
+// Plan finds the actions that make current match the policy. Roles in the policy get exactly the
+// memberships and privileges it lists. Roles that are not in the policy are left alone unless prune
+// is set, in which case they are deleted - apart from the protected roles, e.g. the role making
+// the changes.
+//
+// Roles are created first and deleted last, and grants come before revokes, so that a role never
+// loses access it keeps in the policy partway through.
+func Plan(p *Policy, current State, prune bool, protected ...string) []Action {
+	var creates, adds, grants, revokes, removes, deletes []Action
+
+	for _, r := range p.Roles {
+		have, exists := current.Roles[r.Name]
+
+		if !exists {
+			creates = append(creates, Action{Kind: CreateRole, Role: r.Name, PasswordEnv: r.PasswordEnv})
+		}
+
+		// memberships
+		for _, m := range missing(r.MemberOf, have.MemberOf) {
+			adds = append(adds, Action{Kind: AddMembership, Role: r.Name, Membership: m})
+		}
+
+		for _, m := range missing(have.MemberOf, r.MemberOf) {
+			removes = append(removes, Action{Kind: RemoveMembership, Role: r.Name, Membership: m})
+		}
+
+		// privileges, merging entries for the same resource
+		want := map[string]map[string]bool{}
+		for _, priv := range r.Privileges {
+			// the policy has been validated, so the specifier is valid
+			resource, _ := priv.ResourceSpecifier()
+			spec := resource.String()
+			set, _ := accessTypeSet(priv.AccessTypes)
+
+			if want[spec] == nil {
+				want[spec] = map[string]bool{}
+			}
+
+			for t := range set {
+				want[spec][t] = true
+			}
+		}
+
+		for _, spec := range sortedKeys(want) {
+			got, _ := accessTypeSet(have.Privileges[spec])
+
+			if grant := difference(want[spec], got); len(grant) > 0 {
+				grants = append(grants, Action{Kind: Grant, Role: r.Name, Specifier: spec, AccessTypes: grant})
+			}
+
+			if revoke := difference(got, want[spec]); len(revoke) > 0 {
+				revokes = append(revokes, Action{Kind: Revoke, Role: r.Name, Specifier: spec, AccessTypes: revoke})
+			}
+		}
+
+		for _, spec := range sortedKeys(have.Privileges) {
+			if _, ok := want[spec]; ok {
+				continue
+			}
+
+			got, _ := accessTypeSet(have.Privileges[spec])
+
+			if revoke := difference(got, nil); len(revoke) > 0 {
+				revokes = append(revokes, Action{Kind: Revoke, Role: r.Name, Specifier: spec, AccessTypes: revoke})
+			}
+		}
+	}
+
+	if prune {
+		inPolicy := map[string]bool{}
+		for _, r := range p.Roles {
+			inPolicy[r.Name] = true
+		}
+
+		for _, name := range protected {
+			inPolicy[name] = true
+		}
+
+		for _, name := range sortedKeys(current.Roles) {
+			if !inPolicy[name] {
+				deletes = append(deletes, Action{Kind: DeleteRole, Role: name})
+			}
+		}
+	}
+
+	var actions []Action
+	for _, a := range [][]Action{creates, adds, grants, revokes, removes, deletes} {
+		actions = append(actions, a...)
+	}
+
+	return actions
+}
+
+// accessTypeOrder is the order access types are written in.
+var accessTypeOrder = []string{"read", "write", "grant"}
+
+// accessTypeSet normalises access types, expanding full to read, write and grant.
+func accessTypeSet(types []string) (map[string]bool, error) {
+	set := map[string]bool{}
+
+	for _, t := range types {
+		for _, t := range strings.Split(t, ",") {
+			switch t = strings.ToLower(strings.TrimSpace(t)); t {
+			case "read", "write", "grant":
+				set[t] = true
+			case "full":
+				for _, t := range accessTypeOrder {
+					set[t] = true
+				}
+			case "":
+			default:
+				return nil, fmt.Errorf("unknown access type %s - use read, write, grant or full", t)
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// difference lists the access types in a that are not in b.
+func difference(a, b map[string]bool) []string {
+	var out []string
+
+	for _, t := range accessTypeOrder {
+		if a[t] && !b[t] {
+			out = append(out, t)
+		}
+	}
+
+	return out
+}
+
+// missing lists the names in a that are not in b, in a's order.
+func missing(a, b []string) []string {
+	have := map[string]bool{}
+	for _, s := range b {
+		have[s] = true
+	}
+
+	var out []string
+	for _, s := range a {
+		if !have[s] {
+			out = append(out, s)
+			have[s] = true
+		}
+	}
+
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,162 @@
+// Package policy reads access control policies - the roles, memberships and privileges that should
+// exist on a server - and plans the changes that make a server match one.
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is the complete set of roles that should exist, apart from roles that are left alone
+// because they are not in the policy and are not pruned.
+type Policy struct {
+	Roles []Role `yaml:"roles" json:"roles"`
+}
+
+type Role struct {
+	Name string `yaml:"name" json:"name"`
+
+	// PasswordEnv names the environment variable holding the password, used when the role is created.
+	// Passwords are never stored in the policy.
+	PasswordEnv string `yaml:"password_env" json:"password_env"`
+
+	// MemberOf lists the roles this role is a member of and inherits the privileges of.
+	MemberOf []string `yaml:"member_of" json:"member_of"`
+
+	Privileges []Privilege `yaml:"privileges" json:"privileges"`
+}
+
+// Privilege grants access types on a resource, given by its type, datastore and name, or on a resource
+// specifier written as RDFox writes it, e.g. >roles.
+type Privilege struct {
+	// Type is one of the resource types understood by v6.NewResourceSpecifier. It defaults to datastore.
+	Type string `yaml:"type" json:"type"`
+
+	Datastore string `yaml:"datastore" json:"datastore"`
+
+	// Resource is the name of the resource, or * (the default) for every resource of the type.
+	Resource string `yaml:"resource" json:"resource"`
+
+	Specifier string `yaml:"specifier" json:"specifier"`
+
+	// AccessTypes are read, write, grant or full (all three).
+	AccessTypes []string `yaml:"access_types" json:"access_types"`
+}
+
+// ResourceSpecifier is the resource the privilege applies to.
+func (p Privilege) ResourceSpecifier() (v6.ResourceSpecifier, error) {
+	if p.Specifier != "" {
+		if p.Type != "" || p.Datastore != "" || p.Resource != "" {
+			return v6.ResourceSpecifier{}, errors.New("specifier cannot be set with type, datastore or resource")
+		}
+
+		return v6.ParseResourceSpecifier(p.Specifier)
+	}
+
+	t := v6.ResourceType(p.Type)
+	if t == "" {
+		t = v6.ResourceDatastore
+	}
+
+	return v6.NewResourceSpecifier(t, p.Datastore, p.Resource)
+}
+
+// Load reads a policy from a YAML or JSON file, chosen by the file's extension.
+func Load(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p *Policy
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		p, err = ParseJSON(b)
+	default:
+		p, err = ParseYAML(b)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// ParseYAML reads and validates a YAML policy. Unknown keys are an error, so typos are caught.
+func ParseYAML(b []byte) (*Policy, error) {
+	var p Policy
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, p.Validate()
+}
+
+// ParseJSON reads and validates a JSON policy. Unknown keys are an error, so typos are caught.
+func ParseJSON(b []byte) (*Policy, error) {
+	var p Policy
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, p.Validate()
+}
+
+// Validate checks that every role has a name that is only used once, that roles are not members of
+// themselves, and that privileges name a valid resource and valid access types.
+func (p *Policy) Validate() error {
+	var errs []error
+
+	names := map[string]bool{}
+
+	for i, r := range p.Roles {
+		if r.Name == "" {
+			errs = append(errs, fmt.Errorf("role %d has no name", i+1))
+			continue
+		}
+
+		if names[r.Name] {
+			errs = append(errs, fmt.Errorf("role %s is defined more than once", r.Name))
+		}
+
+		names[r.Name] = true
+
+		for _, m := range r.MemberOf {
+			if m == r.Name {
+				errs = append(errs, fmt.Errorf("role %s cannot be a member of itself", r.Name))
+			}
+		}
+
+		for j, priv := range r.Privileges {
+			if _, err := priv.ResourceSpecifier(); err != nil {
+				errs = append(errs, fmt.Errorf("role %s privilege %d: %w", r.Name, j+1, err))
+			}
+
+			if _, err := accessTypeSet(priv.AccessTypes); err != nil {
+				errs = append(errs, fmt.Errorf("role %s privilege %d: %w", r.Name, j+1, err))
+			} else if len(priv.AccessTypes) == 0 {
+				errs = append(errs, fmt.Errorf("role %s privilege %d has no access types", r.Name, j+1))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
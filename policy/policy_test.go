@@ -0,0 +1,200 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	v6 "github.com/mick-roper/rdfox-cli/rdfox/v6"
+)
+
+const examplePolicy = `
+roles:
+  - name: readers
+    privileges:
+      - datastore: production
+        access_types: [read]
+  - name: alice
+    password_env: ALICE_PASSWORD
+    member_of: [readers]
+    privileges:
+      - type: tuple-table
+        datastore: staging
+        resource: graph1
+        access_types: [read, write]
+      - specifier: ">roles"
+        access_types: [full]
+      - type: named-graph
+        datastore: staging
+        resource: <http://example.com/g>
+        access_types: [read]
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		parse   func([]byte) (*Policy, error)
+		src     string
+		wantErr string
+	}{
+		{name: "yaml", parse: ParseYAML, src: examplePolicy},
+		{
+			name:  "json",
+			parse: ParseJSON,
+			src:   `{"roles": [{"name": "readers", "privileges": [{"datastore": "production", "access_types": ["read"]}]}]}`,
+		},
+		{name: "unknown key", parse: ParseYAML, src: "roles:\n  - name: a\n    memberof: [b]\n", wantErr: "memberof"},
+		{name: "unknown json key", parse: ParseJSON, src: `{"roles": [{"nmae": "a"}]}`, wantErr: "nmae"},
+		{name: "duplicate role", parse: ParseYAML, src: "roles:\n  - name: a\n  - name: a\n", wantErr: "more than once"},
+		{name: "member of itself", parse: ParseYAML, src: "roles:\n  - name: a\n    member_of: [a]\n", wantErr: "itself"},
+		{
+			name:    "unknown access type",
+			parse:   ParseYAML,
+			src:     "roles:\n  - name: a\n    privileges:\n      - datastore: d\n        access_types: [delete]\n",
+			wantErr: "unknown access type delete",
+		},
+		{
+			name:    "role with a datastore",
+			parse:   ParseYAML,
+			src:     "roles:\n  - name: a\n    privileges:\n      - type: role\n        datastore: d\n        access_types: [read]\n",
+			wantErr: "a datastore cannot be set for role resources",
+		},
+		{
+			name:    "datastore and specifier",
+			parse:   ParseYAML,
+			src:     "roles:\n  - name: a\n    privileges:\n      - datastore: d\n        specifier: '>roles'\n        access_types: [read]\n",
+			wantErr: "specifier cannot be set with",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parse([]byte(tt.src))
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("parse error = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	p, err := ParseYAML([]byte(examplePolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := State{Roles: map[string]RoleState{
+		"admin": {},
+		"readers": {
+			Privileges: map[string][]string{">datastores|production": {"read", "write"}},
+		},
+		"bob": {
+			MemberOf: []string{"readers"},
+		},
+		"alice": {
+			MemberOf:   []string{"writers"},
+			Privileges: map[string][]string{"|datastores|staging|tupletables|graph1": {"read"}, ">datastores|old": {"read"}},
+		},
+	}}
+
+	var got []string
+	for _, a := range Plan(p, current, true, "admin") {
+		got = append(got, a.String())
+	}
+
+	want := []string{
+		"+ add alice to readers",
+		"+ grant read,write,grant on >roles to alice",
+		"+ grant write on |datastores|staging|tupletables|graph1 to alice",
+		"+ grant read on |datastores|staging|tupletables|http://example.com/g to alice",
+		"- revoke write on >datastores|production from readers",
+		"- revoke read on >datastores|old from alice",
+		"- remove alice from writers",
+		"- delete role bob",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Plan() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// nothing is deleted without prune, and a new role is created first
+	delete(current.Roles, "alice")
+
+	actions := Plan(p, current, false)
+	if actions[0].Kind != CreateRole || actions[0].Role != "alice" || actions[0].PasswordEnv != "ALICE_PASSWORD" {
+		t.Errorf("Plan()[0] = %v, want create alice", actions[0])
+	}
+
+	for _, a := range actions {
+		if a.Kind == DeleteRole {
+			t.Errorf("Plan() without prune = %v", a)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	state := State{Roles: map[string]RoleState{
+		"admin":   {Privileges: map[string][]string{">": {"full"}}},
+		"readers": {MemberOf: []string{"base"}, Privileges: map[string][]string{">datastores|production": {"read"}}},
+		"base":    {MemberOf: []string{"readers"}, Privileges: map[string][]string{"|roles|base": {"read"}}},
+		"alice": {
+			MemberOf:   []string{"readers"},
+			Privileges: map[string][]string{"|datastores|production|tupletables|graph1": {"read", "write"}},
+		},
+	}}
+
+	production := v6.DatastoreResource("production")
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   []string
+	}{
+		{
+			name:   "role",
+			filter: AuditFilter{Role: "alice"},
+			want: []string{
+				"alice >datastores|production read readers",
+				"alice |datastores|production|tupletables|graph1 read alice",
+				"alice |datastores|production|tupletables|graph1 write alice",
+				"alice |roles|base read base",
+			},
+		},
+		{
+			name:   "direct only",
+			filter: AuditFilter{Role: "alice", DirectOnly: true, Access: "write"},
+			want:   []string{"alice |datastores|production|tupletables|graph1 write alice"},
+		},
+		{
+			name:   "who can write production",
+			filter: AuditFilter{Access: "write", Resource: &production},
+			want:   []string{"admin > write admin"},
+		},
+		{
+			name:   "who can read production",
+			filter: AuditFilter{Access: "read", Resource: &production},
+			want: []string{
+				"admin > read admin",
+				"alice >datastores|production read readers",
+				"base >datastores|production read readers",
+				"readers >datastores|production read readers",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Audit(state, tt.filter) {
+				got = append(got, e.Role+" "+e.Resource+" "+e.Access+" "+e.Via)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Audit() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package v6
+
+import (
+	"net/http"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+)
+
+// Authenticator adds credentials to an outgoing request.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface.
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BasicAuth authenticates every request as role using HTTP basic authentication.
+func BasicAuth(role, password string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
+		return nil
+	})
+}
+
+// NoAuth sends requests without credentials, which RDFox treats as the guest role.
+func NoAuth() Authenticator {
+	return AuthenticatorFunc(func(*http.Request) error {
+		return nil
+	})
+}
@@ -0,0 +1,111 @@
+package v6
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// Client talks to a single RDFox server. It is safe for concurrent use.
+type Client struct {
+	endpoint *url.URL
+	auth     Authenticator
+	client   utils.Client
+}
+
+// NewClient builds a client for the RDFox server at endpoint (e.g. https://rdfox.example.com:12110).
+// If auth is nil requests are sent unauthenticated; if client is nil http.DefaultClient is used.
+func NewClient(endpoint string, auth Authenticator, client utils.Client) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint: %s", endpoint)
+	}
+
+	if auth == nil {
+		auth = NoAuth()
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Client{endpoint: u, auth: auth, client: client}, nil
+}
+
+// Endpoint returns the base URL of the server.
+func (c *Client) Endpoint() string {
+	return c.endpoint.String()
+}
+
+func (c *Client) url(query url.Values, segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, s := range segments {
+		escaped[i] = url.PathEscape(s)
+	}
+
+	u := c.endpoint.JoinPath(escaped...)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func (c *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// do sends req and returns the response if its status is one of expected. Any other status is
+// turned into an *APIError and the response body is closed.
+func (c *Client) do(logger *zap.Logger, req *http.Request, expected ...int) (*http.Response, error) {
+	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
+	logger.Debug("making request...")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		logger.Error("could not make request", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("got response", utils.ResponseToLoggerFields(res)...)
+
+	for _, status := range expected {
+		if res.StatusCode == status {
+			return res, nil
+		}
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Error("bad response from server", zap.String("status", res.Status))
+
+	bytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("could not read response body", zap.Error(err))
+		bytes = []byte(fmt.Sprint("COULD NOT READ RESPONSE BODY: ", err))
+	}
+
+	return nil, newAPIError(res.StatusCode, res.Status, bytes)
+}
+
+func closeBody(logger *zap.Logger, res *http.Response) {
+	if err := res.Body.Close(); err != nil {
+		logger.Error("could not close response body", zap.Error(err))
+	}
+}
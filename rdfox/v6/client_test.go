@@ -0,0 +1,82 @@
+package v6
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		segments []string
+		want     string
+	}{
+		{
+			name:     "no path",
+			endpoint: "https://localhost:12110",
+			segments: []string{"datastores", "default", "connections"},
+			want:     "https://localhost:12110/datastores/default/connections",
+		},
+		{
+			name:     "endpoint with path",
+			endpoint: "https://localhost/rdfox/",
+			segments: []string{"roles"},
+			want:     "https://localhost/rdfox/roles",
+		},
+		{
+			name:     "segments are escaped",
+			endpoint: "http://localhost",
+			segments: []string{"roles", "a/b c"},
+			want:     "http://localhost/roles/a%2Fb%20c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(tt.endpoint, nil, nil)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			if got := c.url(nil, tt.segments...); got != tt.want {
+				t.Errorf("url() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientRejectsRelativeEndpoint(t *testing.T) {
+	if _, err := NewClient("localhost:12110", nil, nil); err == nil {
+		t.Error("NewClient() error = nil, want error")
+	}
+}
+
+func TestClientAuthenticatesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, password, ok := r.BasicAuth()
+		if !ok || role != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Location", "/datastores/default/connections/01234")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, BasicAuth("admin", "secret"), server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	conn, err := c.CreateConnection(context.TODO(), "default")
+	if err != nil {
+		t.Fatalf("CreateConnection() error = %v", err)
+	}
+
+	if conn.ID != "01234" || conn.Datastore != "default" {
+		t.Errorf("CreateConnection() = %+v", conn)
+	}
+}
@@ -2,8 +2,7 @@ package v6
 
 import (
 	"context"
-	"fmt"
-	"io"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -11,105 +10,71 @@ import (
 	"go.uber.org/zap"
 )
 
-func CreateConnection(ctx context.Context, server, protocol, role, password, datastore string) (string, error) {
-	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "create-connection"))
-	client := utils.HttpClientFromContext(ctx)
+// CreateConnection opens a connection to a datastore.
+func (c *Client) CreateConnection(ctx context.Context, datastore string) (*Connection, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "create-connection"), zap.String("datastore", datastore))
 
 	logger.Debug("creating url...")
 
-	url := fmt.Sprintf("%s://%s/datastores/%s/connections", protocol, server, datastore)
+	url := c.url(nil, "datastores", datastore, "connections")
 
 	logger.Debug("url created", zap.String("url", url))
 	logger.Debug("creating request...")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	req, err := c.newRequest(ctx, http.MethodPost, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
-		return "", err
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
-
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusCreated)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
-		return "", err
+		return nil, err
 	}
 
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			logger.Error("could not close response body", zap.Error(err))
-		}
-	}()
+	defer closeBody(logger, res)
 
-	if res.StatusCode != http.StatusCreated {
-		logger.Error("bad response from server", zap.String("status", res.Status))
+	id := res.Header.Get("location")
+	id = id[strings.LastIndex(id, "/")+1:]
 
-		bytes, err := io.ReadAll(res.Body)
-		if err != nil {
-			return "", fmt.Errorf("bad response from server: %s - COULD NOT READ BODY: %s", res.Status, err)
-		}
-
-		return "", fmt.Errorf("bad response from server: %s - %s", res.Status, string(bytes))
-	}
+	logger.Info("connection created", zap.String("connection-id", id))
 
-	c := res.Header.Get("location")
-	c = c[strings.LastIndex(c, "/")+1:]
-
-	logger.Info("connection created", zap.String("connection-id", c))
-
-	return c, nil
+	return &Connection{Datastore: datastore, ID: id, client: c}, nil
 }
 
-func DeleteConnection(ctx context.Context, server, protocol, role, password, datastore, connectionID string) error {
-	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "delete-connection"), zap.String("datastore", datastore), zap.String("connection-id", connectionID))
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) DeleteConnection(ctx context.Context, conn *Connection) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "delete-connection"), zap.String("datastore", conn.Datastore), zap.String("connection-id", conn.ID))
 
 	logger.Debug("creating url...")
 
-	url := fmt.Sprintf("%s://%s/datastores/%s/connections/%s", protocol, server, datastore, connectionID)
+	url := c.url(nil, "datastores", conn.Datastore, "connections", conn.ID)
 
 	logger.Debug("url created", zap.String("url", url))
 	logger.Debug("creating request...")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return err
 	}
 
-	req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
-
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusNoContent)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
 		return err
 	}
 
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			logger.Error("could not close response body", zap.Error(err))
-		}
-	}()
+	closeBody(logger, res)
 
-	if res.StatusCode != http.StatusNoContent {
-		logger.Error("bad response from server", zap.String("status", res.Status))
+	logger.Info("connection closed")
 
-		bytes, err := io.ReadAll(res.Body)
-		if err != nil {
-			return fmt.Errorf("bad response from server: %s - COULD NOT READ BODY: %s", res.Status, err)
-		}
+	return nil
+}
 
-		return fmt.Errorf("bad response from server: %s - %s", res.Status, string(bytes))
+// Close deletes the connection on the server, rolling back any transaction that is still open on it.
+func (conn *Connection) Close(ctx context.Context) error {
+	if conn.client == nil {
+		return errors.New("the connection was not created by a client")
 	}
 
-	logger.Info("connection closed")
-
-	return nil
+	return conn.client.DeleteConnection(ctx, conn)
 }
@@ -0,0 +1,123 @@
+package v6
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// media types understood by the RDFox content endpoint
+const (
+	MediaTypeTurtle   = "text/turtle"
+	MediaTypeNTriples = "application/n-triples"
+	MediaTypeNQuads   = "application/n-quads"
+	MediaTypeTriG     = "application/trig"
+	MediaTypeDatalog  = "application/x.datalog"
+	MediaTypeOWL      = "text/owl-functional"
+)
+
+type ImportOperation string
+
+const (
+	ImportAdd    ImportOperation = "add-content"
+	ImportDelete ImportOperation = "delete-content"
+)
+
+// ImportContent streams RDF or Datalog into (or out of, for ImportDelete) a datastore.
+func (c *Client) ImportContent(ctx context.Context, r ImportRequest) (*ImportResult, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "import-content"), zap.String("datastore", r.Datastore))
+
+	logger.Debug("building url...")
+
+	operation := r.Operation
+	if operation == "" {
+		operation = ImportAdd
+	}
+
+	query := url.Values{"operation": {string(operation)}}
+
+	if r.DefaultGraph != "" {
+		query.Set("default-graph-name", r.DefaultGraph)
+	}
+
+	if r.Connection != nil {
+		query.Set("connection", r.Connection.ID)
+	}
+
+	url := c.url(query, "datastores", r.Datastore, "content")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodPatch, url, r.Body)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", r.ContentType)
+
+	res, err := c.do(logger, req, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Debug("parsing response...")
+
+	summary, err := parseSummary(res.Body)
+	if err != nil {
+		logger.Error("could not read response body", zap.Error(err))
+		return nil, err
+	}
+
+	result := ImportResult{
+		FactsProcessed: summaryCount(summary, summaryProcessedFacts),
+		FactsChanged:   summaryCount(summary, summaryChangedFacts),
+		Errors:         summaryCount(summary, summaryErrors),
+		Warnings:       summaryCount(summary, summaryWarnings),
+		Summary:        summary,
+	}
+
+	logger.Debug("response parsed", zap.Int64("facts-processed", result.FactsProcessed), zap.Int64("facts-changed", result.FactsChanged))
+
+	return &result, nil
+}
+
+// ExportContent exports the contents of a datastore in the format given by the Accept media type: TriG
+// or N-Quads for all graphs, Datalog for the rules, or OWL functional syntax for the axioms. The caller
+// must close the response body.
+func (c *Client) ExportContent(ctx context.Context, r ExportRequest) (*ExportResponse, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "export-content"), zap.String("datastore", r.Datastore))
+
+	logger.Debug("building url...")
+
+	query := url.Values{}
+	if r.Connection != nil {
+		query.Set("connection", r.Connection.ID)
+	}
+
+	url := c.url(query, "datastores", r.Datastore, "content")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Accept", r.Accept)
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportResponse{ContentType: res.Header.Get("Content-Type"), Body: res.Body}, nil
+}
@@ -2,220 +2,274 @@ package v6
 
 import (
 	"bufio"
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
+	"github.com/mick-roper/rdfox-cli/ttl"
 	"github.com/mick-roper/rdfox-cli/utils"
 	"go.uber.org/zap"
 )
 
-func CreateCursor(ctx context.Context, server, protocol, role, password, datastore, connectionID, query string) (string, error) {
-	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "create-cursor"), zap.String("connection-id", connectionID))
-	client := utils.HttpClientFromContext(ctx)
+// DefaultCursorLimit is the number of rows fetched in each page when a cursor is created without a limit.
+const DefaultCursorLimit = 5000
+
+// CreateCursor opens a cursor over the results of a SELECT query on the connection. Nothing is read
+// until Next or NextBatch is called.
+func (c *Client) CreateCursor(ctx context.Context, r CreateCursorRequest) (*Cursor, error) {
+	conn := r.Connection
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "create-cursor"), zap.String("connection-id", conn.ID))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprintf("%s://%s/datastores/%s/connections/%s/cursors", protocol, server, datastore, connectionID)
+	url := c.url(nil, "datastores", conn.Datastore, "connections", conn.ID, "cursors")
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(query))
+	req, err := c.newRequest(ctx, http.MethodPost, url, strings.NewReader(r.Query))
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
-		return "", err
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
 	req.Header.Set("Content-Type", "application/sparql-query")
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("executing request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusCreated)
 	if err != nil {
-		logger.Error("could not execute request", zap.Error(err))
-		return "", err
+		return nil, err
 	}
 
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			logger.Error("could not close response body", zap.Error(err))
-		}
-	}()
-
-	logger.Debug("got response from server")
-
-	if res.StatusCode != http.StatusCreated {
-		logger.Error("bad response from server", zap.String("status", res.Status))
-		bytes, err := io.ReadAll(res.Body)
-		if err != nil {
-			logger.Error("could not read response body", zap.Error(err))
-			return "", fmt.Errorf("bad response from server: %s - COULD NOT READ RESPONSE BODY: %s", res.Status, err)
-		}
-
-		return "", fmt.Errorf("bad response from server: %s - %s", res.Status, string(bytes))
-	}
+	defer closeBody(logger, res)
 
 	logger.Debug("extracting cursor ID from header...")
 
-	c := res.Header.Get("location")
-	c = c[strings.LastIndex(c, "/")+1:]
+	id := res.Header.Get("location")
+	id = id[strings.LastIndex(id, "/")+1:]
+
+	logger.Info("cursor created", zap.String("cursor", id))
 
-	logger.Info("cursor created", zap.String("cursor", c))
+	limit := r.Limit
+	if limit <= 0 {
+		limit = DefaultCursorLimit
+	}
 
-	return c, nil
+	return &Cursor{Connection: conn, ID: id, client: c, limit: limit}, nil
 }
 
-func DeleteCursor(ctx context.Context, server, protocol, role, password, datastore, connectionID, cursorID string) error {
-	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "delete-cursor"), zap.String("connection-id", connectionID), zap.String("cursor-id", cursorID))
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) DeleteCursor(ctx context.Context, cursor *Cursor) error {
+	conn := cursor.Connection
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "delete-cursor"), zap.String("connection-id", conn.ID), zap.String("cursor-id", cursor.ID))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprintf("%s://%s/datastores/%s/connections/%s/cursors/%s", protocol, server, datastore, connectionID, cursorID)
+	url := c.url(nil, "datastores", conn.Datastore, "connections", conn.ID, "cursors", cursor.ID)
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return err
 	}
 
-	req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
-
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("executing request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusNoContent)
 	if err != nil {
-		logger.Error("could not execute request", zap.Error(err))
 		return err
 	}
 
-	defer func() {
-		if err := res.Body.Close(); err != nil {
-			logger.Error("could not close response body", zap.Error(err))
-		}
-	}()
+	closeBody(logger, res)
+
+	logger.Info("cursor closed")
 
-	logger.Debug("got response from server")
+	return nil
+}
+
+// Vars returns the names of the cursor's variables, without the leading '?'. It is empty until the
+// first page has been read.
+func (c *Cursor) Vars() []string {
+	return c.vars
+}
 
-	if res.StatusCode != http.StatusNoContent {
-		logger.Error("bad response from server", zap.String("status", res.Status))
-		bytes, err := io.ReadAll(res.Body)
+// Next returns the next row of results. It returns io.EOF once every row has been read.
+func (c *Cursor) Next(ctx context.Context) (Row, error) {
+	if len(c.page) == 0 {
+		page, err := c.NextBatch(ctx)
 		if err != nil {
-			logger.Error("could not read response body", zap.Error(err))
-			return fmt.Errorf("bad response from server: %s - COULD NOT READ RESPONSE BODY: %s", res.Status, err)
+			return Row{}, err
 		}
 
-		return fmt.Errorf("bad response from server: %s - %s", res.Status, string(bytes))
+		c.page = page
 	}
 
-	logger.Info("cursor closed")
+	row := c.page[0]
+	c.page = c.page[1:]
 
-	return nil
+	return row, nil
 }
 
-func ReadWithCursor(ctx context.Context, server, protocol, role, password, datastore, connectionID, cursorID string, limit int, gotData func(map[string]map[string][]string)) error {
-	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "advance-cursor"), zap.String("connection-id", connectionID), zap.String("cursor-id", cursorID))
-	client := utils.HttpClientFromContext(ctx)
+// NextBatch returns the rest of the current page of results, fetching the next page from the server
+// when the current one has been used up. It returns io.EOF once every row has been read. Nothing is
+// fetched until it is asked for, so a slow reader never has more than one page in memory.
+func (c *Cursor) NextBatch(ctx context.Context) ([]Row, error) {
+	if len(c.page) > 0 {
+		page := c.page
+		c.page = nil
+		return page, nil
+	}
 
-	var read func(op string) error
+	if c.done {
+		return nil, io.EOF
+	}
 
-	read = func(op string) error {
-		logger.Debug("building url...")
+	if c.client == nil {
+		return nil, errors.New("the cursor was not created by a client")
+	}
 
-		url := fmt.Sprintf("%s://%s/datastores/%s/connections/%s/cursors/%s?operation=%s&limit=%d", protocol, server, datastore, connectionID, cursorID, op, limit)
+	op := "advance"
+	if !c.opened {
+		op = "open"
+	}
 
-		logger.Debug("url built", zap.String("url", url))
-		logger.Debug("building request...")
+	page, err := c.client.readCursorPage(ctx, c, op)
+	if err != nil {
+		return nil, err
+	}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, nil)
-		if err != nil {
-			logger.Error("could not build request", zap.Error(err))
-			return err
-		}
+	c.opened = true
 
-		req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
-		req.Header.Set("Accept", "text/tab-separated-values")
+	if len(page) == 0 {
+		c.done = true
+		return nil, io.EOF
+	}
 
-		logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-		logger.Debug("executing request...")
+	return page, nil
+}
 
-		res, err := client.Do(req)
-		if err != nil {
-			logger.Error("could not execute request", zap.Error(err))
-			return err
-		}
+// Close deletes the cursor on the server.
+func (c *Cursor) Close(ctx context.Context) error {
+	if c.client == nil {
+		return errors.New("the cursor was not created by a client")
+	}
 
-		defer res.Body.Close()
+	c.done = true
+	c.page = nil
 
-		logger.Debug("got response from server", zap.String("status", res.Status), zap.Any("header", res.Header))
+	return c.client.DeleteCursor(ctx, c)
+}
 
-		if res.StatusCode != http.StatusOK {
-			logger.Error("bad response from server", zap.String("status", res.Status))
-			bytes, err := io.ReadAll(res.Body)
-			if err != nil {
-				logger.Error("could not read response body", zap.Error(err))
-				return fmt.Errorf("bad response from server: %s - COULD NOT READ RESPONSE BODY: %s", res.Status, err)
-			}
+// Get returns the term bound to the variable name, or nil if it is unbound or not a variable of the row.
+func (r Row) Get(name string) ttl.Term {
+	name = strings.TrimPrefix(name, "?")
 
-			return fmt.Errorf("bad response from server: %s - %s", res.Status, string(bytes))
+	for i, v := range r.vars {
+		if v == name && i < len(r.Terms) {
+			return r.Terms[i]
 		}
+	}
+
+	return nil
+}
 
-		logger.Debug("processing records...")
+// Vars returns the names of the row's variables, without the leading '?'.
+func (r Row) Vars() []string {
+	return r.vars
+}
 
-		data := map[string]map[string][]string{}
-		var i int
-		scanner := bufio.NewScanner(res.Body)
-		scanner.Split(bufio.ScanLines)
-		scanner.Scan() // always do a dumb scan to skip the first line
-		for scanner.Scan() {
-			chunks := strings.Split(scanner.Text(), "\t")
+// readCursorPage opens or advances the cursor and parses the page of results. Each page starts with
+// a header naming the variables, followed by a row per result with each term in Turtle syntax.
+func (c *Client) readCursorPage(ctx context.Context, cursor *Cursor, op string) ([]Row, error) {
+	conn := cursor.Connection
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", op+"-cursor"), zap.String("connection-id", conn.ID), zap.String("cursor-id", cursor.ID))
 
-			if len(chunks) != 3 {
-				logger.Warn("row is the wrong size", zap.Int("row_index", i))
-				continue
-			}
+	logger.Debug("building url...")
 
-			s := string(chunks[0])
-			p := string(chunks[1])
-			o := chunks[2]
+	url := c.url(url.Values{"operation": {op}, "limit": {fmt.Sprint(cursor.limit)}}, "datastores", conn.Datastore, "connections", conn.ID, "cursors", cursor.ID)
 
-			if _, ok := data[s]; !ok {
-				data[s] = map[string][]string{}
-			}
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
 
-			if _, ok := data[s][p]; !ok {
-				data[s][p] = []string{}
-			}
+	// opening or advancing a cursor only reads, so it can be retried
+	req, err := c.newRequest(utils.WithRetrySafe(ctx), http.MethodPatch, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
 
-			data[s][p] = append(data[s][p], o)
+	req.Header.Set("Accept", "text/tab-separated-values")
 
-			i++
-		}
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Debug("processing records...")
+
+	rows, vars, err := parseCursorPage(res.Body)
+	if err != nil {
+		logger.Error("could not read response body", zap.Error(err))
+		return nil, err
+	}
+
+	if len(vars) > 0 {
+		cursor.vars = vars
+	}
+
+	logger.Debug("processed rows", zap.Int("count", len(rows)))
+
+	return rows, nil
+}
+
+func parseCursorPage(r io.Reader) ([]Row, []string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	if !scanner.Scan() {
+		return nil, nil, scanner.Err()
+	}
+
+	header := strings.Split(scanner.Text(), "\t")
+	vars := make([]string, len(header))
+	for i, h := range header {
+		vars[i] = strings.TrimPrefix(strings.TrimSpace(h), "?")
+	}
+
+	var rows []Row
 
-		res.Body.Close()
+	for line := 1; scanner.Scan(); line++ {
+		cells := strings.Split(scanner.Text(), "\t")
 
-		if i == 0 {
-			logger.Debug("no more data to process")
-			return nil
+		if len(cells) != len(vars) {
+			return nil, nil, fmt.Errorf("row %d has %d columns, expected %d", line, len(cells), len(vars))
 		}
 
-		logger.Debug("processed triples", zap.Int("count", i))
+		terms := make([]ttl.Term, len(cells))
+		for i, cell := range cells {
+			if cell == "" {
+				continue
+			}
+
+			t, err := ttl.ParseTerm(cell)
+			if err != nil {
+				return nil, nil, fmt.Errorf("row %d, variable %s: %w", line, vars[i], err)
+			}
 
-		gotData(data)
+			terms[i] = t
+		}
+
+		rows = append(rows, Row{vars: vars, Terms: terms})
+	}
 
-		logger.Debug("cursor has more data - advancing the cursor")
-		return read("advance")
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
 	}
 
-	return read("open")
+	return rows, vars, nil
 }
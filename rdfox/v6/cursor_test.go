@@ -0,0 +1,116 @@
+package v6
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+func TestParseCursorPage(t *testing.T) {
+	body := "?s\t?label\t?n\n" +
+		"<http://ex/a>\t\"A\"@en\t1\n" +
+		"_:b0\t\t\"2\"^^<http://www.w3.org/2001/XMLSchema#integer>\n"
+
+	rows, vars, err := parseCursorPage(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parseCursorPage() error = %v", err)
+	}
+
+	if strings.Join(vars, ",") != "s,label,n" {
+		t.Errorf("vars = %v, want [s label n]", vars)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+
+	if got := rows[0].Get("?label"); got != (ttl.Literal{Value: "A", Lang: "en"}) {
+		t.Errorf("Get(label) = %v", got)
+	}
+
+	if got := rows[1].Get("label"); got != nil {
+		t.Errorf("Get(label) = %v, want nil for an unbound variable", got)
+	}
+
+	if got := rows[1].Get("s"); got != ttl.BlankNode("b0") {
+		t.Errorf("Get(s) = %v", got)
+	}
+}
+
+func TestParseCursorPageRejectsShortRows(t *testing.T) {
+	if _, _, err := parseCursorPage(strings.NewReader("?s\t?p\n<http://ex/a>\n")); err == nil {
+		t.Error("parseCursorPage() error = nil, want error")
+	}
+}
+
+func TestCursorNext(t *testing.T) {
+	rows := []string{"<http://ex/1>", "<http://ex/2>", "<http://ex/3>"}
+	var offset int
+	var ops []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", r.URL.Path+"/c1")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		ops = append(ops, r.URL.Query().Get("operation"))
+
+		end := offset + 2
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		io.WriteString(w, "?s\n")
+		for _, row := range rows[offset:end] {
+			io.WriteString(w, row+"\n")
+		}
+
+		offset = end
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	cursor, err := client.CreateCursor(ctx, CreateCursorRequest{Connection: &Connection{Datastore: "default", ID: "1"}, Query: "SELECT ?s WHERE { ?s ?p ?o }", Limit: 2})
+	if err != nil {
+		t.Fatalf("CreateCursor() error = %v", err)
+	}
+
+	var got []string
+	for {
+		row, err := cursor.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+
+		got = append(got, row.Get("s").String())
+	}
+
+	if strings.Join(got, " ") != strings.Join(rows, " ") {
+		t.Errorf("rows = %v, want %v", got, rows)
+	}
+
+	if strings.Join(ops, ",") != "open,advance,advance" {
+		t.Errorf("operations = %v, want open,advance,advance", ops)
+	}
+
+	if _, err := cursor.Next(ctx); err != io.EOF {
+		t.Errorf("Next() after the end error = %v, want io.EOF", err)
+	}
+}
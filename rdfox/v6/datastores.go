@@ -0,0 +1,143 @@
+package v6
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+func (c *Client) ListDatastores(ctx context.Context) ([]string, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "list-datastores"))
+
+	logger.Debug("building url...")
+
+	url := c.url(nil, "datastores")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/csv")
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Debug("parsing response...")
+
+	datastores := []string{}
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Split(bufio.ScanLines)
+	scanner.Scan() // always do this to ignore the first line
+
+	for scanner.Scan() {
+		datastores = append(datastores, scanner.Text())
+	}
+
+	logger.Debug("response parsed!")
+
+	return datastores, nil
+}
+
+// CreateDatastore creates a datastore. The parameters are passed to RDFox as is, e.g. type=parallel-nn.
+func (c *Client) CreateDatastore(ctx context.Context, r CreateDatastoreRequest) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "create-datastore"), zap.String("datastore", r.Name))
+
+	logger.Debug("building url...")
+
+	query := url.Values{}
+	for k, v := range r.Parameters {
+		query.Set(k, v)
+	}
+
+	url := c.url(query, "datastores", r.Name)
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return err
+	}
+
+	res, err := c.do(logger, req, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+
+	closeBody(logger, res)
+
+	logger.Info("datastore created")
+
+	return nil
+}
+
+func (c *Client) DeleteDatastore(ctx context.Context, name string) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "delete-datastore"), zap.String("datastore", name))
+
+	logger.Debug("building url...")
+
+	url := c.url(nil, "datastores", name)
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return err
+	}
+
+	res, err := c.do(logger, req, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+
+	closeBody(logger, res)
+
+	logger.Info("datastore deleted")
+
+	return nil
+}
+
+// GetDatastoreInfo returns the datastore's properties and parameters, grouped by component.
+func (c *Client) GetDatastoreInfo(ctx context.Context, name string) (Statistics, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "get-datastore-info"), zap.String("datastore", name))
+
+	logger.Debug("building url...")
+
+	url := c.url(url.Values{"component-info": {"short"}}, "datastores", name)
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "*/*")
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	return parseStats(res.Body), nil
+}
@@ -0,0 +1,84 @@
+package v6
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// APIError is returned when RDFox answers a request with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	Status     string
+
+	// Type is the RDFox exception name from the response body (e.g. DatastoreNotFoundException), if any.
+	Type    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("bad response from server: %s - %s: %s", e.Status, e.Type, e.Message)
+	}
+
+	if e.Message != "" {
+		return fmt.Sprintf("bad response from server: %s - %s", e.Status, e.Message)
+	}
+
+	return fmt.Sprintf("bad response from server: %s", e.Status)
+}
+
+// RDFox reports errors as '<ExceptionName>: <message>' in a plain text body.
+var errorTypePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]*(?:Exception|Error)):\s*`)
+
+func newAPIError(status int, statusText string, body []byte) *APIError {
+	e := APIError{
+		StatusCode: status,
+		Status:     statusText,
+		Message:    strings.TrimSpace(string(body)),
+	}
+
+	if m := errorTypePattern.FindStringSubmatch(e.Message); m != nil {
+		e.Type = m[1]
+		e.Message = strings.TrimSpace(e.Message[len(m[0]):])
+	}
+
+	return &e
+}
+
+func hasStatus(err error, statuses ...int) bool {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return false
+	}
+
+	for _, s := range statuses {
+		if e.StatusCode == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNotFound reports whether err is an APIError for a resource that does not exist.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError caused by missing credentials or privileges.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is an APIError for a resource that already exists or is in use.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsBadRequest reports whether err is an APIError caused by an invalid request, such as a SPARQL syntax error.
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
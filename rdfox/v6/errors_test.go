@@ -0,0 +1,65 @@
+package v6
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantType    string
+		wantMessage string
+	}{
+		{
+			name:        "rdfox exception",
+			body:        "DatastoreNotFoundException: The datastore 'x' does not exist.\n",
+			wantType:    "DatastoreNotFoundException",
+			wantMessage: "The datastore 'x' does not exist.",
+		},
+		{
+			name:        "plain message",
+			body:        "something went wrong: badly",
+			wantType:    "",
+			wantMessage: "something went wrong: badly",
+		},
+		{
+			name: "empty body",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newAPIError(http.StatusNotFound, "404 Not Found", []byte(tt.body))
+
+			if got.Type != tt.wantType {
+				t.Errorf("Type = %v, want %v", got.Type, tt.wantType)
+			}
+
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %v, want %v", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	wrapped := fmt.Errorf("could not create datastore: %w", newAPIError(http.StatusConflict, "409 Conflict", nil))
+
+	if !IsConflict(wrapped) {
+		t.Error("IsConflict() = false, want true")
+	}
+
+	if IsNotFound(wrapped) {
+		t.Error("IsNotFound() = true, want false")
+	}
+
+	if !IsUnauthorized(newAPIError(http.StatusForbidden, "403 Forbidden", nil)) {
+		t.Error("IsUnauthorized() = false, want true")
+	}
+
+	if IsNotFound(fmt.Errorf("not an api error")) {
+		t.Error("IsNotFound() = true, want false")
+	}
+}
@@ -0,0 +1,91 @@
+package v6
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+type ExplanationType string
+
+const (
+	// ExplainShortest finds one shortest proof of the fact.
+	ExplainShortest ExplanationType = "shortest"
+
+	// ExplainToExplicit finds every way the fact is derived from explicit facts.
+	ExplainToExplicit ExplanationType = "to-explicit"
+
+	// ExplainExhaustive finds every way the fact is derived, including from other derived facts.
+	ExplainExhaustive ExplanationType = "exhaustive"
+)
+
+const mediaTypeExplanation = "application/x.explanation+json"
+
+// Explain explains how a fact was derived by reasoning.
+func (c *Client) Explain(ctx context.Context, r ExplainRequest) (*Explanation, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "explain"), zap.String("datastore", r.Datastore))
+
+	logger.Debug("building url...")
+
+	query := url.Values{"fact": {r.Fact}}
+
+	if r.Type != "" {
+		query.Set("type", string(r.Type))
+	}
+
+	if r.MaxDepth > 0 {
+		query.Set("max-distance-from-root", strconv.Itoa(r.MaxDepth))
+	}
+
+	if r.MaxRuleInstances > 0 {
+		query.Set("max-rule-instances-per-fact", strconv.Itoa(r.MaxRuleInstances))
+	}
+
+	url := c.url(query, "datastores", r.Datastore, "explanation")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Accept", mediaTypeExplanation)
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Debug("parsing response...")
+
+	var explanation Explanation
+	if err := json.NewDecoder(res.Body).Decode(&explanation); err != nil {
+		logger.Error("could not parse response", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("response parsed", zap.Int("facts", len(explanation.Facts)), zap.Bool("complete", explanation.Complete))
+
+	return &explanation, nil
+}
+
+// Fact finds a fact in the explanation by its ID.
+func (e *Explanation) Fact(id int) (*ExplanationFact, bool) {
+	for i := range e.Facts {
+		if e.Facts[i].ID == id {
+			return &e.Facts[i], true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,52 @@
+package v6
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("fact"); got != "[<a>, <p>, <b>]" {
+			t.Errorf("fact = %v", got)
+		}
+
+		if got := r.URL.Query().Get("max-distance-from-root"); got != "3" {
+			t.Errorf("max-distance-from-root = %v, want 3", got)
+		}
+
+		w.Write([]byte(`{"prefixes": {}, "complete": true, "facts": [
+			{"id": 0, "fact": "[<a>, <p>, <b>]", "type": "derived", "rule-instances": [
+				{"rule": "[?x, <p>, ?y] :- [?y, <q>, ?x], NOT [?x, <r>, ?y] .", "grounded-rule": "[<a>, <p>, <b>] :- [<b>, <q>, <a>], NOT [<a>, <r>, <b>] .", "body-facts": [1, null]}
+			]},
+			{"id": 1, "fact": "[<b>, <q>, <a>]", "type": "explicit", "rule-instances": []}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	e, err := client.Explain(context.Background(), ExplainRequest{Datastore: "default", Fact: "[<a>, <p>, <b>]", MaxDepth: 3})
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+
+	root, ok := e.Fact(0)
+	if !ok || len(root.RuleInstances) != 1 {
+		t.Fatalf("Fact(0) = %v, %v", root, ok)
+	}
+
+	body := root.RuleInstances[0].BodyFacts
+	if len(body) != 2 || body[0] == nil || *body[0] != 1 || body[1] != nil {
+		t.Errorf("body facts = %v, want [1 nil]", body)
+	}
+
+	if _, ok := e.Fact(2); ok {
+		t.Errorf("Fact(2) found a fact that is not in the explanation")
+	}
+}
@@ -3,61 +3,98 @@ package v6
 import (
 	"bufio"
 	"context"
-	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/mick-roper/rdfox-cli/utils"
 	"go.uber.org/zap"
 )
 
-func ImportAxioms(ctx context.Context, protocol, server, role, password, datastore, srcGraph, dstGraph string) error {
-	logger := utils.LoggerFromContext(ctx)
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) ImportAxioms(ctx context.Context, r ImportAxiomsRequest) (*ImportAxiomsResponse, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "import-axioms"), zap.String("datastore", r.Datastore))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprintf("%s://%s/datastores/%s/content?operation=add-axioms&source-graph=%s&destination-graph=%s", protocol, server, datastore, srcGraph, dstGraph)
+	query := url.Values{
+		"operation":         {"add-axioms"},
+		"source-graph":      {r.SourceGraph},
+		"destination-graph": {r.DestinationGraph},
+	}
+	url := c.url(query, "datastores", r.Datastore, "content")
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, nil)
+	req, err := c.newRequest(ctx, http.MethodPatch, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
-		return err
+		return nil, err
+	}
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	var response ImportAxiomsResponse
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Split(bufio.ScanLines)
+
+	for scanner.Scan() {
+		if s := strings.TrimSpace(scanner.Text()); s != "" {
+			response.Messages = append(response.Messages, s)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		logger.Error("could not read response body", zap.Error(err))
+		return nil, err
 	}
 
-	req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
+	return &response, nil
+}
+
+// Compact runs the shell 'compact' command against datastore.
+func (c *Client) Compact(ctx context.Context, datastore string) (string, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "compact"), zap.String("datastore", datastore))
+
+	logger.Debug("building url...")
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
+	url := c.url(nil, "commands")
 
-	logger.Debug("making request...")
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building command...")
+
+	command := "active " + datastore + "\ncompact"
+
+	logger.Debug("command built", zap.String("command", command))
+	logger.Debug("building request...")
 
-	res, err := client.Do(req)
+	req, err := c.newRequest(ctx, http.MethodPost, url, strings.NewReader(command))
 	if err != nil {
-		logger.Error("could not get response", zap.Error(err))
-		return err
+		logger.Error("could not build request", zap.Error(err))
+		return "", err
 	}
 
-	defer res.Body.Close()
+	req.Header.Set("Content-Type", "text/plain")
 
-	if res.StatusCode != http.StatusOK {
-		payload, err := io.ReadAll(res.Body)
-		if err != nil {
-			return fmt.Errorf("bad response from server: %s - COULD NOT READ RESPONSE: %s", res.Status, err)
-		}
-		return fmt.Errorf("bad response from server: %s %s", res.Status, string(payload))
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return "", err
 	}
 
-	scanner := bufio.NewScanner(res.Body)
-	scanner.Split(bufio.ScanLines)
+	defer closeBody(logger, res)
 
-	for scanner.Scan() {
-		s := strings.TrimSpace(scanner.Text())
-		logger.Info("success", zap.String("data", s))
+	payload, err := io.ReadAll(res.Body)
+	if err != nil {
+		logger.Error("could not read response body", zap.Error(err))
+		return "", err
 	}
 
-	return nil
+	return string(payload), nil
 }
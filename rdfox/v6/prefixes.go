@@ -0,0 +1,117 @@
+package v6
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// GetPrefixes gets the prefixes of a datastore. The names do not have a trailing colon, and the
+// default prefix is named "".
+func (c *Client) GetPrefixes(ctx context.Context, datastore string) (ttl.Prefixes, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "get-prefixes"), zap.String("datastore", datastore))
+
+	logger.Debug("building url...")
+
+	url := c.url(nil, "datastores", datastore, "prefixes")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/csv")
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Debug("parsing response...")
+
+	prefixes, err := parsePrefixes(res.Body)
+	if err != nil {
+		logger.Error("could not parse response", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Debug("response parsed", zap.Int("prefixes", len(prefixes)))
+
+	return prefixes, nil
+}
+
+// SetPrefix adds a prefix to a datastore, or changes the IRI of an existing one.
+func (c *Client) SetPrefix(ctx context.Context, datastore, name, iri string) error {
+	return c.updatePrefix(ctx, datastore, url.Values{"operation": {"add-prefix"}, "prefix": {name + ":"}, "iri": {iri}})
+}
+
+func (c *Client) DeletePrefix(ctx context.Context, datastore, name string) error {
+	return c.updatePrefix(ctx, datastore, url.Values{"operation": {"delete-prefix"}, "prefix": {name + ":"}})
+}
+
+func (c *Client) updatePrefix(ctx context.Context, datastore string, query url.Values) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", query.Get("operation")), zap.String("datastore", datastore), zap.String("prefix", query.Get("prefix")))
+
+	logger.Debug("building url...")
+
+	url := c.url(query, "datastores", datastore, "prefixes")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodPatch, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return err
+	}
+
+	res, err := c.do(logger, req, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+
+	closeBody(logger, res)
+
+	logger.Debug("prefix updated")
+
+	return nil
+}
+
+// parsePrefixes reads the PrefixName,PrefixIRI CSV returned by the prefixes endpoint.
+func parsePrefixes(r io.Reader) (ttl.Prefixes, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := ttl.Prefixes{}
+
+	// the first record is the header
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+
+		if len(record) != 2 {
+			return nil, fmt.Errorf("expected 2 columns on line %d but got %d", i+1, len(record))
+		}
+
+		prefixes[strings.TrimSuffix(record[0], ":")] = record[1]
+	}
+
+	return prefixes, nil
+}
@@ -0,0 +1,46 @@
+package v6
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+func TestParsePrefixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    ttl.Prefixes
+		wantErr bool
+	}{
+		{
+			name: "prefixes",
+			body: "PrefixName,PrefixIRI\n:,http://example.com/\nowl:,http://www.w3.org/2002/07/owl#\n",
+			want: ttl.Prefixes{"": "http://example.com/", "owl": "http://www.w3.org/2002/07/owl#"},
+		},
+		{
+			name: "no prefixes",
+			body: "PrefixName,PrefixIRI\n",
+			want: ttl.Prefixes{},
+		},
+		{
+			name:    "missing column",
+			body:    "PrefixName\nowl:\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePrefixes(strings.NewReader(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePrefixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePrefixes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
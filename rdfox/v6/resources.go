@@ -0,0 +1,186 @@
+package v6
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ResourceSpecifier identifies the resources a privilege applies to. RDFox writes it as a path of
+// components separated by '|', starting with '|' for the resource itself or '>' for the resource and
+// every resource under it, e.g. |datastores|myStore or >roles.
+type ResourceSpecifier struct {
+	// Path is the components of the resource, e.g. datastores, myStore, tupletables, myTable. An empty
+	// path is the server.
+	Path []string
+
+	// Recursive includes every resource under Path.
+	Recursive bool
+}
+
+type ResourceType string
+
+// the resource types understood by NewResourceSpecifier
+const (
+	ResourceAll        ResourceType = "all"
+	ResourceDatastore  ResourceType = "datastore"
+	ResourceTupleTable ResourceType = "tuple-table"
+	ResourceDataSource ResourceType = "data-source"
+	ResourceNamedGraph ResourceType = "named-graph"
+	ResourceRole       ResourceType = "role"
+)
+
+var ResourceTypes = []ResourceType{ResourceAll, ResourceDatastore, ResourceTupleTable, ResourceDataSource, ResourceNamedGraph, ResourceRole}
+
+// AllResources is every resource on the server.
+func AllResources() ResourceSpecifier {
+	return ResourceSpecifier{Recursive: true}
+}
+
+// DatastoreResource is a datastore and everything in it, or every datastore if datastore is *.
+func DatastoreResource(datastore string) ResourceSpecifier {
+	if datastore == "*" {
+		return ResourceSpecifier{Path: []string{"datastores"}, Recursive: true}
+	}
+
+	return ResourceSpecifier{Path: []string{"datastores", datastore}, Recursive: true}
+}
+
+// TupleTableResource is a tuple table in a datastore, or every tuple table if name is *.
+func TupleTableResource(datastore, name string) ResourceSpecifier {
+	return datastoreComponent(datastore, "tupletables", name)
+}
+
+// DataSourceResource is a data source in a datastore, or every data source if name is *.
+func DataSourceResource(datastore, name string) ResourceSpecifier {
+	return datastoreComponent(datastore, "datasources", name)
+}
+
+// NamedGraphResource is a named graph in a datastore. RDFox stores each named graph in a tuple table
+// named by the graph's IRI, so this is the same as TupleTableResource.
+func NamedGraphResource(datastore, graph string) ResourceSpecifier {
+	return TupleTableResource(datastore, strings.Trim(graph, "<>"))
+}
+
+// RoleResource is a role, or every role if name is *.
+func RoleResource(name string) ResourceSpecifier {
+	if name == "*" {
+		return ResourceSpecifier{Path: []string{"roles"}, Recursive: true}
+	}
+
+	return ResourceSpecifier{Path: []string{"roles", name}}
+}
+
+func datastoreComponent(datastore, kind, name string) ResourceSpecifier {
+	if name == "*" {
+		return ResourceSpecifier{Path: []string{"datastores", datastore, kind}, Recursive: true}
+	}
+
+	return ResourceSpecifier{Path: []string{"datastores", datastore, kind, name}}
+}
+
+// NewResourceSpecifier builds a resource specifier from a resource type, a datastore and the name of
+// the resource, where * means every resource of the type. For ResourceDatastore the name is * for the
+// datastore and everything in it, or a path under the datastore such as tupletables|myTable.
+func NewResourceSpecifier(t ResourceType, datastore, name string) (ResourceSpecifier, error) {
+	if name == "" {
+		name = "*"
+	}
+
+	needsDatastore := t != ResourceAll && t != ResourceRole
+
+	if needsDatastore && datastore == "" {
+		return ResourceSpecifier{}, fmt.Errorf("a datastore is needed for %s resources", t)
+	}
+
+	if !needsDatastore && datastore != "" {
+		return ResourceSpecifier{}, fmt.Errorf("a datastore cannot be set for %s resources", t)
+	}
+
+	if strings.Contains(datastore, "|") || (t != ResourceDatastore && strings.Contains(name, "|")) {
+		return ResourceSpecifier{}, errors.New("resource names cannot contain '|'")
+	}
+
+	switch t {
+	case ResourceAll:
+		if name != "*" {
+			return ResourceSpecifier{}, errors.New("a resource name cannot be set for all resources")
+		}
+
+		return AllResources(), nil
+	case ResourceDatastore:
+		if name == "*" {
+			return DatastoreResource(datastore), nil
+		}
+
+		return ResourceSpecifier{Path: append([]string{"datastores", datastore}, strings.Split(name, "|")...)}, nil
+	case ResourceTupleTable:
+		return TupleTableResource(datastore, name), nil
+	case ResourceDataSource:
+		return DataSourceResource(datastore, name), nil
+	case ResourceNamedGraph:
+		if name == "*" {
+			return ResourceSpecifier{}, errors.New("a named graph resource needs the graph's IRI - use tuple-table with * for every graph")
+		}
+
+		return NamedGraphResource(datastore, name), nil
+	case ResourceRole:
+		return RoleResource(name), nil
+	default:
+		return ResourceSpecifier{}, fmt.Errorf("unknown resource type %s - use one of %v", t, ResourceTypes)
+	}
+}
+
+// ParseResourceSpecifier reads a resource specifier as RDFox writes it.
+func ParseResourceSpecifier(s string) (ResourceSpecifier, error) {
+	var r ResourceSpecifier
+
+	switch {
+	case strings.HasPrefix(s, ">"):
+		r.Recursive = true
+	case strings.HasPrefix(s, "|"):
+	default:
+		return ResourceSpecifier{}, fmt.Errorf("a resource specifier must start with '|' or '>': %s", s)
+	}
+
+	if rest := s[1:]; rest != "" {
+		r.Path = strings.Split(rest, "|")
+	}
+
+	for _, c := range r.Path {
+		if c == "" {
+			return ResourceSpecifier{}, fmt.Errorf("a resource specifier cannot have an empty component: %s", s)
+		}
+	}
+
+	return r, nil
+}
+
+func (r ResourceSpecifier) String() string {
+	prefix := "|"
+	if r.Recursive {
+		prefix = ">"
+	}
+
+	return prefix + strings.Join(r.Path, "|")
+}
+
+// Covers reports whether a privilege on r applies to other - either they are the same resource, or r
+// is recursive and other is under it.
+func (r ResourceSpecifier) Covers(other ResourceSpecifier) bool {
+	if len(r.Path) > len(other.Path) {
+		return false
+	}
+
+	for i, c := range r.Path {
+		if other.Path[i] != c {
+			return false
+		}
+	}
+
+	if len(r.Path) == len(other.Path) {
+		return r.Recursive || !other.Recursive
+	}
+
+	return r.Recursive
+}
@@ -0,0 +1,128 @@
+package v6
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestNewResourceSpecifier(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType ResourceType
+		datastore    string
+		resource     string
+		want         string
+		wantErr      bool
+	}{
+		{"everything", ResourceAll, "", "*", ">", false},
+		{"datastore", ResourceDatastore, "ds", "*", ">datastores|ds", false},
+		{"every datastore", ResourceDatastore, "*", "*", ">datastores", false},
+		{"path in a datastore", ResourceDatastore, "ds", "tupletables|tt", "|datastores|ds|tupletables|tt", false},
+		{"tuple table", ResourceTupleTable, "ds", "tt", "|datastores|ds|tupletables|tt", false},
+		{"every tuple table", ResourceTupleTable, "ds", "", ">datastores|ds|tupletables", false},
+		{"data source", ResourceDataSource, "ds", "src", "|datastores|ds|datasources|src", false},
+		{"named graph", ResourceNamedGraph, "ds", "<http://ex.com/g>", "|datastores|ds|tupletables|http://ex.com/g", false},
+		{"role", ResourceRole, "", "alice", "|roles|alice", false},
+		{"every role", ResourceRole, "", "*", ">roles", false},
+		{"datastore missing", ResourceTupleTable, "", "tt", "", true},
+		{"datastore for a role", ResourceRole, "ds", "alice", "", true},
+		{"named for all", ResourceAll, "", "x", "", true},
+		{"every named graph", ResourceNamedGraph, "ds", "*", "", true},
+		{"separator in a name", ResourceRole, "", "a|b", "", true},
+		{"unknown type", ResourceType("server"), "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewResourceSpecifier(tt.resourceType, tt.datastore, tt.resource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewResourceSpecifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("NewResourceSpecifier() = %v, want %v", got, tt.want)
+			}
+
+			parsed, err := ParseResourceSpecifier(tt.want)
+			if err != nil {
+				t.Fatalf("ParseResourceSpecifier() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(parsed, got) {
+				t.Errorf("ParseResourceSpecifier() = %#v, want %#v", parsed, got)
+			}
+		})
+	}
+}
+
+func TestParseResourceSpecifierRejectsInvalid(t *testing.T) {
+	for _, s := range []string{"", "datastores|ds", "|datastores||ds"} {
+		if _, err := ParseResourceSpecifier(s); err == nil {
+			t.Errorf("ParseResourceSpecifier(%q) error = nil, want an error", s)
+		}
+	}
+}
+
+func TestGrantPrivilegesEncodesBody(t *testing.T) {
+	var body url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, _ = url.ParseQuery(string(b))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	req := UpdatePrivilegesRequest{Role: "alice", Resource: NamedGraphResource("ds", "http://ex.com/g?a=1&b=2"), AccessTypes: "read,write"}
+
+	if err := client.GrantPrivileges(context.Background(), req); err != nil {
+		t.Fatalf("GrantPrivileges() error = %v", err)
+	}
+
+	if got := body.Get("resource-specifier"); got != "|datastores|ds|tupletables|http://ex.com/g?a=1&b=2" {
+		t.Errorf("resource-specifier = %v", got)
+	}
+
+	if got := body.Get("access-types"); got != "read,write" {
+		t.Errorf("access-types = %v", got)
+	}
+}
+
+func TestResourceSpecifierCovers(t *testing.T) {
+	tests := []struct {
+		r, other string
+		want     bool
+	}{
+		{">", "|datastores|ds", true},
+		{">datastores|ds", "|datastores|ds", true},
+		{">datastores|ds", "|datastores|ds|tupletables|tt", true},
+		{"|datastores|ds", "|datastores|ds", true},
+		{"|datastores|ds", "|datastores|ds|tupletables|tt", false},
+		{"|datastores|ds", ">datastores|ds", false},
+		{">datastores|ds", ">datastores|ds2", false},
+		{"|datastores|ds|tupletables|tt", ">datastores|ds", false},
+		{">roles", "|datastores|ds", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.r+" "+tt.other, func(t *testing.T) {
+			r, _ := ParseResourceSpecifier(tt.r)
+			other, _ := ParseResourceSpecifier(tt.other)
+
+			if got := r.Covers(other); got != tt.want {
+				t.Errorf("Covers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
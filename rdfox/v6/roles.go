@@ -4,49 +4,36 @@ import (
 	"bufio"
 	"context"
 	"errors"
-	"fmt"
-	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/mick-roper/rdfox-cli/utils"
 	"go.uber.org/zap"
 )
 
-func GetRoles(ctx context.Context, server, protocol, role, password string) ([]string, error) {
-	logger := utils.LoggerFromContext(ctx)
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) GetRoles(ctx context.Context) ([]string, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "get-roles"))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprint(protocol, "://", server, "/roles")
+	url := c.url(nil, "roles")
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := utils.NewRequest(http.MethodGet, url, role, password, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return nil, err
 	}
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusOK)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
 		return nil, err
 	}
 
-	defer res.Body.Close()
-
-	logger.Debug("got response", utils.ResponseToLoggerFields(res)...)
-
-	if res.StatusCode != http.StatusOK {
-		logger.Error("bad response from server", zap.String("status", res.Status))
-		return nil, fmt.Errorf("bad response from server: %s", res.Status)
-	}
+	defer closeBody(logger, res)
 
 	logger.Debug("parsing response...")
 
@@ -64,18 +51,17 @@ func GetRoles(ctx context.Context, server, protocol, role, password string) ([]s
 	return roles, nil
 }
 
-func CreateRole(ctx context.Context, server, protocol, role, password, newRoleName, newRolePassword string) error {
-	logger := utils.LoggerFromContext(ctx)
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) CreateRole(ctx context.Context, r CreateRoleRequest) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "create-role"), zap.String("role", r.Name))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprint(protocol, "://", server, "/roles/", newRoleName)
+	url := c.url(nil, "roles", r.Name)
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := utils.NewRequest(http.MethodPost, url, role, password, strings.NewReader(newRolePassword))
+	req, err := c.newRequest(ctx, http.MethodPost, url, strings.NewReader(r.Password))
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return err
@@ -83,98 +69,70 @@ func CreateRole(ctx context.Context, server, protocol, role, password, newRoleNa
 
 	req.Header.Set("Content-Type", "text/plain")
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusCreated)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
 		return err
 	}
 
-	defer res.Body.Close()
-
-	logger.Debug("got response", utils.ResponseToLoggerFields(res)...)
-
-	if res.StatusCode != http.StatusCreated {
-		logger.Error("bad response from server", zap.String("status", res.Status))
-		return fmt.Errorf("bad response from server: %s", res.Status)
-	}
+	closeBody(logger, res)
 
 	return nil
 }
 
-func DeleteRole(ctx context.Context, server, protocol, role, password, roleToDelete string) error {
-	logger := utils.LoggerFromContext(ctx)
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) DeleteRole(ctx context.Context, name string) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "delete-role"), zap.String("role", name))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprint(protocol, "://", server, "/roles/", roleToDelete)
+	url := c.url(nil, "roles", name)
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := utils.NewRequest(http.MethodDelete, url, role, password, nil)
+	req, err := c.newRequest(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return err
 	}
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusNoContent)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
 		return err
 	}
 
-	defer res.Body.Close()
-
-	logger.Debug("got response", utils.ResponseToLoggerFields(res)...)
-
-	if res.StatusCode != http.StatusNoContent {
-		logger.Error("bad response from server", zap.String("status", res.Status))
-		return fmt.Errorf("bad response from server: %s", res.Status)
-	}
+	closeBody(logger, res)
 
 	return nil
 }
 
-func GrantDatastorePrivileges(ctx context.Context, server, protocol, role, password, targetRole, datastore, resource, accessTypes string) error {
-	return updateDatastoreAccessTypes(ctx, server, protocol, role, password, targetRole, "grant", datastore, resource, accessTypes)
+func (c *Client) GrantPrivileges(ctx context.Context, r UpdatePrivilegesRequest) error {
+	return c.updatePrivileges(ctx, "grant", r)
 }
 
-func RevokeDatastorePrivileges(ctx context.Context, server, protocol, role, password, targetRole, datastore, resource, accessTypes string) error {
-	return updateDatastoreAccessTypes(ctx, server, protocol, role, password, targetRole, "revoke", datastore, resource, accessTypes)
+func (c *Client) RevokePrivileges(ctx context.Context, r UpdatePrivilegesRequest) error {
+	return c.updatePrivileges(ctx, "revoke", r)
 }
 
-func updateDatastoreAccessTypes(ctx context.Context, server, protocol, role, password, targetRole, operation, datastore, resource, accessTypes string) error {
-	logger := utils.LoggerFromContext(ctx)
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) updatePrivileges(ctx context.Context, operation string, r UpdatePrivilegesRequest) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", operation+"-privileges"), zap.String("role", r.Role))
 
 	if operation != "grant" && operation != "revoke" {
 		return errors.New("only 'grant' and 'revoke' operations are supported")
 	}
 
-	logger.Debug("building url...")
-
-	url := fmt.Sprint(protocol, "://", server, "/roles/", targetRole, "/privileges?operation=", operation)
-
-	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building body string...")
 
-	bodyString := fmt.Sprintf("resource-specifier=>datastores|%s&access-types=%s", datastore, accessTypes)
-
-	if resource != "*" {
-		bodyString = fmt.Sprintf("resource-specifier=|datastores|%s|%s&access-types=%s", datastore, resource, accessTypes)
-	}
+	bodyString := url.Values{"resource-specifier": {r.Resource.String()}, "access-types": {r.AccessTypes}}.Encode()
 
 	logger.Debug("body string built", zap.String("content", bodyString))
+	logger.Debug("building url...")
+
+	url := c.url(url.Values{"operation": {operation}}, "roles", r.Role, "privileges")
+
+	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := utils.NewRequest(http.MethodPatch, url, role, password, strings.NewReader(bodyString))
+	req, err := c.newRequest(ctx, http.MethodPatch, url, strings.NewReader(bodyString))
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return err
@@ -182,41 +140,27 @@ func updateDatastoreAccessTypes(ctx context.Context, server, protocol, role, pas
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusOK)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
 		return err
 	}
 
-	defer res.Body.Close()
-
-	logger.Debug("got response", utils.ResponseToLoggerFields(res)...)
-
-	if res.StatusCode != http.StatusOK {
-		bytes, _ := io.ReadAll(res.Body)
-
-		logger.Error("bad response from server", zap.String("status", res.Status), zap.ByteString("content", bytes))
-		return fmt.Errorf("bad response from server: %s", res.Status)
-	}
+	closeBody(logger, res)
 
 	return nil
 }
 
-func ListPrivileges(ctx context.Context, server, protocol, role, password, targetRole string) (map[string][]string, error) {
-	logger := utils.LoggerFromContext(ctx)
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) ListPrivileges(ctx context.Context, role string) (Privileges, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "list-privileges"), zap.String("role", role))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprint(protocol, "://", server, "/roles/", targetRole, "/privileges")
+	url := c.url(nil, "roles", role, "privileges")
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request...")
 
-	req, err := utils.NewRequest(http.MethodGet, url, role, password, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return nil, err
@@ -224,25 +168,14 @@ func ListPrivileges(ctx context.Context, server, protocol, role, password, targe
 
 	req.Header.Set("Accept", "text/csv")
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusOK)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
 		return nil, err
 	}
 
-	defer res.Body.Close()
-
-	logger.Debug("got response", utils.ResponseToLoggerFields(res)...)
+	defer closeBody(logger, res)
 
-	if res.StatusCode != http.StatusOK {
-		logger.Error("bad response from server", zap.String("status", res.Status))
-		return nil, fmt.Errorf("bad response from server: %s", res.Status)
-	}
-
-	p := map[string][]string{}
+	p := Privileges{}
 
 	scanner := bufio.NewScanner(res.Body)
 	scanner.Split(bufio.ScanLines)
@@ -264,3 +197,158 @@ func ListPrivileges(ctx context.Context, server, protocol, role, password, targe
 
 	return p, nil
 }
+
+// ChangePassword changes the password of the role the client is authenticated as.
+func (c *Client) ChangePassword(ctx context.Context, oldPassword, newPassword string) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "change-password"))
+
+	if strings.ContainsAny(newPassword, "\r\n") {
+		return errors.New("a password cannot contain a line break")
+	}
+
+	logger.Debug("building url...")
+
+	url := c.url(nil, "password")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	// the body is the current password and the new password, each on its own line
+	req, err := c.newRequest(ctx, http.MethodPut, url, strings.NewReader(oldPassword+"\n"+newPassword))
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain")
+
+	res, err := c.do(logger, req, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+
+	closeBody(logger, res)
+
+	return nil
+}
+
+// GetMemberships gets the roles that role is a direct member of, and so inherits the privileges of.
+func (c *Client) GetMemberships(ctx context.Context, role string) ([]string, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "get-memberships"), zap.String("role", role))
+
+	logger.Debug("building url...")
+
+	url := c.url(nil, "roles", role, "memberships")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/csv")
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Debug("parsing response...")
+
+	roles := []string{}
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Split(bufio.ScanLines)
+	scanner.Scan() // always do this to ignore the first line
+
+	for scanner.Scan() {
+		if name := strings.Trim(scanner.Text(), " \""); name != "" {
+			roles = append(roles, name)
+		}
+	}
+
+	logger.Debug("response parsed!")
+
+	return roles, nil
+}
+
+// GetInheritedMemberships gets the roles that role is a direct member of, and the roles it inherits
+// through them. Each role is only reported once, even if the memberships form a cycle.
+func (c *Client) GetInheritedMemberships(ctx context.Context, role string) (direct, inherited []string, err error) {
+	direct, err = c.GetMemberships(ctx, role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := map[string]bool{role: true}
+	for _, r := range direct {
+		seen[r] = true
+	}
+
+	queue := append([]string{}, direct...)
+
+	for len(queue) > 0 {
+		next, err := c.GetMemberships(ctx, queue[0])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		queue = queue[1:]
+
+		for _, r := range next {
+			if seen[r] {
+				continue
+			}
+
+			seen[r] = true
+			inherited = append(inherited, r)
+			queue = append(queue, r)
+		}
+	}
+
+	return direct, inherited, nil
+}
+
+// AddMembership makes member a member of role, so that member inherits role's privileges.
+func (c *Client) AddMembership(ctx context.Context, member, role string) error {
+	return c.updateMemberships(ctx, "add", member, role)
+}
+
+// RemoveMembership stops member being a member of role.
+func (c *Client) RemoveMembership(ctx context.Context, member, role string) error {
+	return c.updateMemberships(ctx, "delete", member, role)
+}
+
+func (c *Client) updateMemberships(ctx context.Context, operation, member, role string) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", operation+"-membership"), zap.String("role", member), zap.String("membership", role))
+
+	body := url.Values{"role-name": {role}}.Encode()
+
+	logger.Debug("building url...")
+
+	url := c.url(url.Values{"operation": {operation}}, "roles", member, "memberships")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodPatch, url, strings.NewReader(body))
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.do(logger, req, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+
+	closeBody(logger, res)
+
+	return nil
+}
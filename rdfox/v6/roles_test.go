@@ -0,0 +1,44 @@
+package v6
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetInheritedMemberships(t *testing.T) {
+	memberships := map[string][]string{
+		"alice":    {"analysts"},
+		"analysts": {"readers", "alice"},
+		"readers":  {"guests"},
+		"guests":   {},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/roles/"), "/memberships")
+
+		w.Write([]byte("Name\n" + strings.Join(memberships[role], "\n")))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	direct, inherited, err := client.GetInheritedMemberships(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("GetInheritedMemberships() error = %v", err)
+	}
+
+	if want := []string{"analysts"}; !reflect.DeepEqual(direct, want) {
+		t.Errorf("direct = %v, want %v", direct, want)
+	}
+
+	if want := []string{"readers", "guests"}; !reflect.DeepEqual(inherited, want) {
+		t.Errorf("inherited = %v, want %v", inherited, want)
+	}
+}
@@ -0,0 +1,56 @@
+package v6
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// Query evaluates a SPARQL query against a datastore. The caller must close the response body.
+func (c *Client) Query(ctx context.Context, r QueryRequest) (*QueryResponse, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "query"), zap.String("datastore", r.Datastore))
+
+	logger.Debug("building url...")
+
+	query := url.Values{}
+	if r.Connection != nil {
+		query.Set("connection", r.Connection.ID)
+	}
+
+	if r.DefaultGraph != "" {
+		query.Set("default-graph-uri", r.DefaultGraph)
+	}
+
+	if r.Explain {
+		query.Set("explain", "true")
+	}
+
+	url := c.url(query, "datastores", r.Datastore, "sparql")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	// queries are sent with POST so they can be any length, but they only read, so they can be retried
+	req, err := c.newRequest(utils.WithRetrySafe(ctx), http.MethodPost, url, strings.NewReader(r.Query))
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/sparql-query")
+
+	if r.Accept != "" {
+		req.Header.Set("Accept", r.Accept)
+	}
+
+	res, err := c.do(logger, req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryResponse{ContentType: res.Header.Get("Content-Type"), Body: res.Body}, nil
+}
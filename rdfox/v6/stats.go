@@ -3,55 +3,44 @@ package v6
 import (
 	"bufio"
 	"context"
-	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/mick-roper/rdfox-cli/utils"
 	"go.uber.org/zap"
 )
 
-func GetStats(ctx context.Context, server, protocol, role, password, datastore string) (Statistics, error) {
-	logger := utils.LoggerFromContext(ctx)
-	client := utils.HttpClientFromContext(ctx)
+func (c *Client) GetStats(ctx context.Context, datastore string) (Statistics, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "get-stats"))
 
 	logger.Debug("building url...")
 
-	url := fmt.Sprint(protocol, "://", server)
+	segments := []string{}
 	if datastore != "" {
-		url = fmt.Sprint(url, "/datastores/", datastore)
+		segments = append(segments, "datastores", datastore)
 	}
-	url = fmt.Sprint(url, "?component-info=extended")
+
+	url := c.url(url.Values{"component-info": {"extended"}}, segments...)
 
 	logger.Debug("url built", zap.String("url", url))
 	logger.Debug("building request")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := c.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		logger.Error("could not build request", zap.Error(err))
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", utils.BasicAuthHeaderValue(role, password))
 	req.Header.Set("Accept", "*/*")
 
-	logger.Debug("request built", utils.RequestToLoggerFields(req)...)
-	logger.Debug("making request...")
-
-	res, err := client.Do(req)
+	res, err := c.do(logger, req, http.StatusOK)
 	if err != nil {
-		logger.Error("could not make request", zap.Error(err))
 		return nil, err
 	}
 
-	defer res.Body.Close()
-
-	logger.Debug("got response", zap.String("status", res.Status), zap.Any("headers", res.Header))
-
-	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("bad response from server: %s", res.Status)
-	}
+	defer closeBody(logger, res)
 
 	return parseStats(res.Body), nil
 }
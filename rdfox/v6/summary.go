@@ -0,0 +1,64 @@
+package v6
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// the statistics RDFox returns after importing content or running an update
+const (
+	summaryErrors         = "errors"
+	summaryWarnings       = "warnings"
+	summaryProcessedFacts = "processedFacts"
+	summaryChangedFacts   = "changedFacts"
+	summaryAddedFacts     = "addedFacts"
+	summaryDeletedFacts   = "deletedFacts"
+)
+
+// parseSummary reads the statistics RDFox returns after changing data. The body is Turtle that
+// describes a blank node with one rdfox:name value pair per line:
+//
+//	prefix rdfox: <https://rdfox.com/vocabulary#>
+//	[] rdfox:processedFacts 9 ;
+//	   rdfox:changedFacts 8 .
+//
+// The summary is keyed by the names without the rdfox: prefix. Comments and other lines are skipped.
+func parseSummary(r io.Reader) (map[string]string, error) {
+	summary := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimSpace(strings.TrimPrefix(line, "[]"))
+		line = strings.TrimSpace(strings.TrimRight(line, ";."))
+
+		name, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+
+		name, ok = strings.CutPrefix(name, "rdfox:")
+		if !ok {
+			continue
+		}
+
+		// typed literals, e.g. "9"^^xsd:long, are reduced to their value
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, "^^"); i > 0 {
+			value = value[:i]
+		}
+
+		summary[name] = strings.Trim(value, `"`)
+	}
+
+	return summary, scanner.Err()
+}
+
+func summaryCount(summary map[string]string, name string) int64 {
+	n, _ := strconv.ParseInt(summary[name], 10, 64)
+	return n
+}
@@ -0,0 +1,67 @@
+package v6
+
+import (
+	"strings"
+	"testing"
+)
+
+// importResponse and updateResponse are response bodies in the form RDFox v6 returns them.
+const (
+	importResponse = `prefix rdfox: <https://rdfox.com/vocabulary#>
+prefix xsd: <http://www.w3.org/2001/XMLSchema#>
+
+# aggregate statistics
+[] rdfox:errors 1 ;
+   rdfox:warnings 2 ;
+   rdfox:processedFacts 9 ;
+   rdfox:changedFacts 8 .
+`
+
+	updateResponse = `prefix rdfox: <https://rdfox.com/vocabulary#>
+prefix xsd: <http://www.w3.org/2001/XMLSchema#>
+
+[] rdfox:addedFacts "6"^^xsd:long ;
+   rdfox:deletedFacts "2"^^xsd:long .
+`
+)
+
+func TestParseSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]int64
+	}{
+		{
+			name: "import",
+			body: importResponse,
+			want: map[string]int64{summaryErrors: 1, summaryWarnings: 2, summaryProcessedFacts: 9, summaryChangedFacts: 8},
+		},
+		{
+			name: "update",
+			body: updateResponse,
+			want: map[string]int64{summaryAddedFacts: 6, summaryDeletedFacts: 2},
+		},
+		{
+			name: "empty",
+			want: map[string]int64{summaryAddedFacts: 0, summaryErrors: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := parseSummary(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("parseSummary() error = %v", err)
+			}
+
+			for name, want := range tt.want {
+				if got := summaryCount(summary, name); got != want {
+					t.Errorf("%s = %v, want %v", name, got, want)
+				}
+			}
+
+			if len(summary) != len(tt.want) && tt.body != "" {
+				t.Errorf("parseSummary() = %v, want %d statistics", summary, len(tt.want))
+			}
+		})
+	}
+}
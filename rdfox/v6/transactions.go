@@ -0,0 +1,151 @@
+package v6
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// ErrTransactionDone is returned when a transaction is used after it has been committed or rolled back.
+var ErrTransactionDone = errors.New("the transaction has already been committed or rolled back")
+
+// BeginTransaction starts a transaction on conn. Every request made on the connection until the
+// transaction is committed or rolled back runs inside it.
+func (c *Client) BeginTransaction(ctx context.Context, conn *Connection, readOnly bool) error {
+	txType := "read-write"
+	if readOnly {
+		txType = "read-only"
+	}
+
+	return c.transaction(ctx, conn, http.MethodPost, url.Values{"type": {txType}}, "begin-transaction")
+}
+
+func (c *Client) CommitTransaction(ctx context.Context, conn *Connection) error {
+	return c.transaction(ctx, conn, http.MethodPatch, url.Values{"operation": {"commit"}}, "commit-transaction")
+}
+
+func (c *Client) RollbackTransaction(ctx context.Context, conn *Connection) error {
+	return c.transaction(ctx, conn, http.MethodPatch, url.Values{"operation": {"rollback"}}, "rollback-transaction")
+}
+
+func (c *Client) transaction(ctx context.Context, conn *Connection, method string, query url.Values, op string) error {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", op), zap.String("datastore", conn.Datastore), zap.String("connection-id", conn.ID))
+
+	logger.Debug("building url...")
+
+	url := c.url(query, "datastores", conn.Datastore, "connections", conn.ID, "transaction")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, method, url, nil)
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return err
+	}
+
+	res, err := c.do(logger, req, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+
+	closeBody(logger, res)
+
+	logger.Debug("transaction updated")
+
+	return nil
+}
+
+// Begin starts a transaction on the connection.
+func (conn *Connection) Begin(ctx context.Context, readOnly bool) (*Transaction, error) {
+	if conn.client == nil {
+		return nil, errors.New("the connection was not created by a client")
+	}
+
+	if err := conn.client.BeginTransaction(ctx, conn, readOnly); err != nil {
+		return nil, err
+	}
+
+	return &Transaction{Connection: conn}, nil
+}
+
+// Commit applies the transaction. The transaction cannot be used after it has been committed, but
+// if the commit fails it can still be rolled back.
+func (tx *Transaction) Commit(ctx context.Context) error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+
+	if err := tx.Connection.client.CommitTransaction(ctx, tx.Connection); err != nil {
+		return err
+	}
+
+	tx.done = true
+
+	return nil
+}
+
+// Rollback discards the transaction. It does nothing if the transaction has already been committed
+// or rolled back, so it can be deferred straight after Begin.
+func (tx *Transaction) Rollback(ctx context.Context) error {
+	if tx.done {
+		return nil
+	}
+
+	tx.done = true
+
+	return tx.Connection.client.RollbackTransaction(ctx, tx.Connection)
+}
+
+// Update runs a SPARQL update inside the transaction.
+func (tx *Transaction) Update(ctx context.Context, update string) (*UpdateResult, error) {
+	if tx.done {
+		return nil, ErrTransactionDone
+	}
+
+	return tx.Connection.client.Update(ctx, UpdateRequest{Datastore: tx.Connection.Datastore, Update: update, Connection: tx.Connection})
+}
+
+// Import adds or deletes content inside the transaction. The request's datastore and connection are
+// set from the transaction.
+func (tx *Transaction) Import(ctx context.Context, r ImportRequest) (*ImportResult, error) {
+	if tx.done {
+		return nil, ErrTransactionDone
+	}
+
+	r.Datastore = tx.Connection.Datastore
+	r.Connection = tx.Connection
+
+	return tx.Connection.client.ImportContent(ctx, r)
+}
+
+// Query runs a SPARQL query inside the transaction, so it sees the transaction's changes. The
+// request's datastore and connection are set from the transaction.
+func (tx *Transaction) Query(ctx context.Context, r QueryRequest) (*QueryResponse, error) {
+	if tx.done {
+		return nil, ErrTransactionDone
+	}
+
+	r.Datastore = tx.Connection.Datastore
+	r.Connection = tx.Connection
+
+	return tx.Connection.client.Query(ctx, r)
+}
+
+// Export exports content inside the transaction, so it includes the transaction's changes. The
+// request's datastore and connection are set from the transaction. The caller must close the
+// response body.
+func (tx *Transaction) Export(ctx context.Context, r ExportRequest) (*ExportResponse, error) {
+	if tx.done {
+		return nil, ErrTransactionDone
+	}
+
+	r.Datastore = tx.Connection.Datastore
+	r.Connection = tx.Connection
+
+	return tx.Connection.client.ExportContent(ctx, r)
+}
@@ -0,0 +1,111 @@
+package v6
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/connections"):
+			w.Header().Set("Location", r.URL.Path+"/42")
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	conn, err := client.CreateConnection(ctx, "default")
+	if err != nil {
+		t.Fatalf("CreateConnection() error = %v", err)
+	}
+
+	tx, err := conn.Begin(ctx, false)
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+
+	if _, err := tx.Update(ctx, "CLEAR DEFAULT"); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if err := tx.Rollback(ctx); err != nil {
+		t.Errorf("Rollback() after Commit() error = %v, want nil", err)
+	}
+
+	if _, err := tx.Update(ctx, "CLEAR DEFAULT"); err != ErrTransactionDone {
+		t.Errorf("Update() after Commit() error = %v, want ErrTransactionDone", err)
+	}
+
+	want := []string{
+		"POST /datastores/default/connections?",
+		"POST /datastores/default/connections/42/transaction?type=read-write",
+		"POST /datastores/default/sparql?connection=42",
+		"PATCH /datastores/default/connections/42/transaction?operation=commit",
+	}
+
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests =\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTransactionRollbackAfterFailedCommit(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+
+		if r.URL.Query().Get("operation") == "commit" {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, nil, server.Client())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx := context.Background()
+	tx := &Transaction{Connection: &Connection{Datastore: "default", ID: "42", client: client}}
+
+	if err := tx.Commit(ctx); err == nil {
+		t.Fatalf("Commit() error = nil, want the server's error")
+	}
+
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+
+	want := []string{
+		"PATCH /datastores/default/connections/42/transaction?operation=commit",
+		"PATCH /datastores/default/connections/42/transaction?operation=rollback",
+	}
+
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests =\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
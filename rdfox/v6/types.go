@@ -1,5 +1,204 @@
 package v6
 
+import (
+	"io"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
 type (
 	Statistics map[string]map[string]interface{}
+
+	// Privileges maps a resource specifier to the access types granted on it.
+	Privileges map[string][]string
+
+	// Connection is a session on a datastore. Connections returned by CreateConnection can begin
+	// transactions with Begin, and must be closed.
+	Connection struct {
+		Datastore string
+		ID        string
+
+		client *Client
+	}
+
+	// Transaction is a read-write or read-only transaction on a connection. Every operation run
+	// through it is applied atomically when it is committed.
+	Transaction struct {
+		Connection *Connection
+
+		done bool
+	}
+
+	// Cursor reads the results of a query a page at a time. Cursors returned by CreateCursor can be
+	// iterated with Next or NextBatch, and must be closed.
+	Cursor struct {
+		Connection *Connection
+		ID         string
+
+		client *Client
+		limit  int
+		vars   []string
+		page   []Row
+		opened bool
+		done   bool
+	}
+
+	CreateCursorRequest struct {
+		Connection *Connection
+		Query      string
+
+		// Limit is the number of rows fetched from the server in each page. Defaults to DefaultCursorLimit.
+		Limit int
+	}
+
+	// Row is one result read from a cursor. Terms holds a term per variable, in the order of the
+	// cursor's Vars, and nil where a variable is unbound.
+	Row struct {
+		vars  []string
+		Terms []ttl.Term
+	}
+
+	ImportAxiomsRequest struct {
+		Datastore        string
+		SourceGraph      string
+		DestinationGraph string
+	}
+
+	ImportAxiomsResponse struct {
+		Messages []string
+	}
+
+	CreateRoleRequest struct {
+		Name     string
+		Password string
+	}
+
+	CreateDatastoreRequest struct {
+		Name       string
+		Parameters map[string]string
+	}
+
+	QueryRequest struct {
+		Datastore string
+		Query     string
+
+		// Accept is the media type the results should be returned in, e.g. application/sparql-results+json.
+		Accept string
+
+		// DefaultGraph is optional - set it to query a named graph as the default graph.
+		DefaultGraph string
+
+		// Explain asks RDFox to explain how it evaluates the query.
+		Explain bool
+
+		// Connection is optional - set it to run the query inside a transaction opened with BeginTransaction.
+		Connection *Connection
+	}
+
+	QueryResponse struct {
+		ContentType string
+		Body        io.ReadCloser
+	}
+
+	UpdateRequest struct {
+		Datastore string
+		Update    string
+
+		// Connection is optional - set it to run the update inside a transaction opened with BeginTransaction.
+		Connection *Connection
+	}
+
+	UpdateResult struct {
+		FactsAdded   int64
+		FactsDeleted int64
+
+		// Summary holds every statistic RDFox reported, keyed by name without the rdfox: prefix, e.g. addedFacts.
+		Summary map[string]string
+	}
+
+	ImportRequest struct {
+		Datastore   string
+		ContentType string
+		Body        io.Reader
+
+		// Operation defaults to ImportAdd.
+		Operation ImportOperation
+
+		// DefaultGraph is the graph triples without an explicit graph are imported into.
+		DefaultGraph string
+
+		Connection *Connection
+	}
+
+	ImportResult struct {
+		FactsProcessed int64
+		FactsChanged   int64
+		Errors         int64
+		Warnings       int64
+		Summary        map[string]string
+	}
+
+	ExportRequest struct {
+		Datastore string
+		Accept    string
+
+		Connection *Connection
+	}
+
+	ExportResponse struct {
+		ContentType string
+		Body        io.ReadCloser
+	}
+
+	UpdatePrivilegesRequest struct {
+		Role        string
+		Resource    ResourceSpecifier
+		AccessTypes string
+	}
+
+	ExplainRequest struct {
+		Datastore string
+
+		// Fact is the fact to explain, written as a Datalog atom, e.g. [<s>, <p>, <o>].
+		Fact string
+
+		// Type is the kind of explanation: ExplainShortest (the default), ExplainToExplicit or ExplainExhaustive.
+		Type ExplanationType
+
+		// MaxDepth and MaxRuleInstances limit the size of the explanation. Zero means the server's default.
+		MaxDepth         int
+		MaxRuleInstances int
+	}
+
+	// Explanation is a proof of a fact. The fact with ID 0 is the fact being explained, and each
+	// derived fact lists the rule instances that derived it.
+	Explanation struct {
+		Prefixes map[string]string `json:"prefixes"`
+
+		// Complete is false if the explanation was cut short by MaxDepth or MaxRuleInstances.
+		Complete bool `json:"complete"`
+
+		Facts []ExplanationFact `json:"facts"`
+	}
+
+	ExplanationFact struct {
+		ID   int    `json:"id"`
+		Fact string `json:"fact"`
+
+		// Type is explicit, derived or false (for a fact that does not hold).
+		Type string `json:"type"`
+
+		RuleInstances []RuleInstance `json:"rule-instances"`
+	}
+
+	RuleInstance struct {
+		Rule string `json:"rule"`
+
+		// GroundedRule is the rule with its variables replaced by the values it fired with.
+		GroundedRule string `json:"grounded-rule"`
+
+		// BodyFacts are the IDs of the facts that matched the rule's body. An ID is nil for a body atom
+		// that does not match a fact, e.g. a negation or a filter.
+		BodyFacts []*int `json:"body-facts"`
+	}
 )
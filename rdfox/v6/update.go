@@ -0,0 +1,62 @@
+package v6
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/utils"
+	"go.uber.org/zap"
+)
+
+// Update evaluates a SPARQL 1.1 Update against a datastore. If the request names a connection the
+// update runs on that connection, and so inside any transaction open on it.
+func (c *Client) Update(ctx context.Context, r UpdateRequest) (*UpdateResult, error) {
+	logger := utils.LoggerFromContext(ctx).With(zap.String("op", "update"), zap.String("datastore", r.Datastore))
+
+	logger.Debug("building url...")
+
+	query := url.Values{}
+	if r.Connection != nil {
+		query.Set("connection", r.Connection.ID)
+	}
+
+	url := c.url(query, "datastores", r.Datastore, "sparql")
+
+	logger.Debug("url built", zap.String("url", url))
+	logger.Debug("building request...")
+
+	req, err := c.newRequest(ctx, http.MethodPost, url, strings.NewReader(r.Update))
+	if err != nil {
+		logger.Error("could not build request", zap.Error(err))
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/sparql-update")
+
+	res, err := c.do(logger, req, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(logger, res)
+
+	logger.Debug("parsing response...")
+
+	summary, err := parseSummary(res.Body)
+	if err != nil {
+		logger.Error("could not read response body", zap.Error(err))
+		return nil, err
+	}
+
+	result := UpdateResult{
+		FactsAdded:   summaryCount(summary, summaryAddedFacts),
+		FactsDeleted: summaryCount(summary, summaryDeletedFacts),
+		Summary:      summary,
+	}
+
+	logger.Debug("response parsed", zap.Int64("facts-added", result.FactsAdded), zap.Int64("facts-deleted", result.FactsDeleted))
+
+	return &result, nil
+}
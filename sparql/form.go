@@ -0,0 +1,114 @@
+package sparql
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Form is the kind of a SPARQL query, which determines the shape of its results.
+type Form string
+
+const (
+	Select    Form = "SELECT"
+	Ask       Form = "ASK"
+	Construct Form = "CONSTRUCT"
+	Describe  Form = "DESCRIBE"
+	Unknown   Form = ""
+)
+
+// ReturnsGraph is true for query forms whose results are RDF rather than variable bindings.
+func (f Form) ReturnsGraph() bool {
+	return f == Construct || f == Describe
+}
+
+// QueryForm finds the form of query by skipping comments and the BASE/PREFIX prologue.
+func QueryForm(query string) Form {
+	p := prologue{src: query}
+
+	for {
+		switch word := strings.ToUpper(p.keyword()); word {
+		case "BASE":
+			if !p.iri() {
+				return Unknown
+			}
+		case "PREFIX":
+			if !p.prefixName() || !p.iri() {
+				return Unknown
+			}
+		case string(Select), string(Ask), string(Construct), string(Describe):
+			return Form(word)
+		default:
+			return Unknown
+		}
+	}
+}
+
+// prologue reads the start of a query token by token. The prologue may be written without
+// whitespace, e.g. PREFIX ex:<http://example.com/>SELECT.
+type prologue struct {
+	src string
+	pos int
+}
+
+func (p *prologue) skipSpace() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case unicode.IsSpace(rune(c)):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// keyword reads a run of letters, so that 'SELECT*' and 'ASK{' read as SELECT and ASK.
+func (p *prologue) keyword() string {
+	p.skipSpace()
+
+	start := p.pos
+	for p.pos < len(p.src) && unicode.IsLetter(rune(p.src[p.pos])) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// prefixName reads a prefix name up to and including its ':'. The name may be empty.
+func (p *prologue) prefixName() bool {
+	p.skipSpace()
+
+	for p.pos < len(p.src) {
+		switch c := rune(p.src[p.pos]); {
+		case c == ':':
+			p.pos++
+			return true
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-' || c == '.' || c >= 0x80:
+			p.pos++
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+func (p *prologue) iri() bool {
+	p.skipSpace()
+
+	if p.pos >= len(p.src) || p.src[p.pos] != '<' {
+		return false
+	}
+
+	end := iriEnd(p.src, p.pos)
+	if end < 0 {
+		return false
+	}
+
+	p.pos = end + 1
+
+	return true
+}
@@ -0,0 +1,31 @@
+package sparql
+
+import "testing"
+
+func TestQueryForm(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  Form
+	}{
+		{"select", "SELECT ?s ?p ?o WHERE { ?s ?p ?o }", Select},
+		{"select star", "select* { ?s ?p ?o }", Select},
+		{"ask", "ASK{ ?s ?p ?o }", Ask},
+		{"construct with prologue", "BASE <http://example.com/>\nPREFIX ex: <http://example.com/#>\nCONSTRUCT { ?s ?p ?o } WHERE { ?s ?p ?o }", Construct},
+		{"describe", "describe <http://example.com/a>", Describe},
+		{"comments", "# SELECT in a comment\nPREFIX : <http://example.com/#> # ASK\nCONSTRUCT WHERE { ?s ?p ?o }", Construct},
+		{"prefix without spaces", "PREFIX ex:<http://example.com/#>SELECT * WHERE { ?s ex:p ?o }", Select},
+		{"empty prefix name", "PREFIX : <http://example.com/#>\nASK { ?s :p ?o }", Ask},
+		{"prefix named like a form", "PREFIX select: <http://example.com/#> INSERT DATA { <a> <b> <c> }", Unknown},
+		{"update with prologue", "PREFIX ex: <http://example.com/#>\nINSERT DATA { ex:a ex:b ex:c }", Unknown},
+		{"update", "INSERT DATA { <a> <b> <c> }", Unknown},
+		{"empty", "", Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QueryForm(tt.query); got != tt.want {
+				t.Errorf("QueryForm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package sparql
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+// Results are the decoded application/sparql-results+json output of a SELECT or ASK query.
+type Results struct {
+	Head struct {
+		Vars []string `json:"vars"`
+	} `json:"head"`
+	Boolean *bool `json:"boolean,omitempty"`
+	Results struct {
+		Bindings []map[string]Binding `json:"bindings"`
+	} `json:"results"`
+}
+
+// Binding is a single RDF term bound to a variable.
+type Binding struct {
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Datatype string `json:"datatype,omitempty"`
+	Lang     string `json:"xml:lang,omitempty"`
+}
+
+// String renders the term the way it would be written in Turtle.
+func (b Binding) String() string {
+	return b.Format(nil)
+}
+
+// Format renders the term the way it would be written in Turtle, writing IRIs as prefixed names where
+// one of the prefixes matches.
+func (b Binding) Format(prefixes ttl.Prefixes) string {
+	switch b.Type {
+	case "uri":
+		return formatIRI(b.Value, prefixes)
+	case "bnode":
+		return "_:" + b.Value
+	case "literal", "typed-literal":
+		s := `"` + escaper.Replace(b.Value) + `"`
+
+		if b.Lang != "" {
+			return s + "@" + b.Lang
+		}
+
+		if b.Datatype != "" && b.Datatype != "http://www.w3.org/2001/XMLSchema#string" {
+			return s + "^^" + formatIRI(b.Datatype, prefixes)
+		}
+
+		return s
+	default:
+		return b.Value
+	}
+}
+
+func formatIRI(iri string, prefixes ttl.Prefixes) string {
+	if s, ok := prefixes.Compact(iri); ok {
+		return s
+	}
+
+	return "<" + iri + ">"
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func ReadResults(r io.Reader) (*Results, error) {
+	var res Results
+	if err := json.NewDecoder(r).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
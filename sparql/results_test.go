@@ -0,0 +1,32 @@
+package sparql
+
+import (
+	"testing"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+func TestBindingFormat(t *testing.T) {
+	prefixes := ttl.Prefixes{"ex": "http://example.com/", "xsd": "http://www.w3.org/2001/XMLSchema#"}
+
+	tests := []struct {
+		name     string
+		binding  Binding
+		prefixes ttl.Prefixes
+		want     string
+	}{
+		{"iri", Binding{Type: "uri", Value: "http://example.com/a"}, prefixes, "ex:a"},
+		{"iri without prefixes", Binding{Type: "uri", Value: "http://example.com/a"}, nil, "<http://example.com/a>"},
+		{"iri with no matching prefix", Binding{Type: "uri", Value: "http://other.com/a"}, prefixes, "<http://other.com/a>"},
+		{"typed literal", Binding{Type: "literal", Value: "1", Datatype: "http://www.w3.org/2001/XMLSchema#integer"}, prefixes, `"1"^^xsd:integer`},
+		{"language literal", Binding{Type: "literal", Value: "a \"b\"", Lang: "en"}, prefixes, `"a \"b\""@en`},
+		{"blank node", Binding{Type: "bnode", Value: "b0"}, prefixes, "_:b0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.binding.Format(tt.prefixes); got != tt.want {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
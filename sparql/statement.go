@@ -0,0 +1,88 @@
+package sparql
+
+import "strings"
+
+// Terminated reports whether a query typed a line at a time is complete: it ends with a ';' that is
+// outside braces, literals, IRIs and comments. An update is never terminated by ';', since ';' also
+// separates the operations of a multi-operation update.
+func Terminated(statement string) bool {
+	return QueryForm(statement) != Unknown && endsWithSemicolon(statement)
+}
+
+// endsWithSemicolon reports whether the last token of s is a ';' outside braces, literals, IRIs
+// and comments.
+func endsWithSemicolon(s string) bool {
+	depth := 0
+	var last byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+
+			continue
+		case '"', '\'':
+			quote := s[i : i+1]
+			if strings.HasPrefix(s[i:], strings.Repeat(quote, 3)) {
+				quote = strings.Repeat(quote, 3)
+			}
+
+			end := closingQuote(s, i+len(quote), quote)
+			if end < 0 {
+				return false
+			}
+
+			i = end + len(quote) - 1
+		case '<':
+			if end := iriEnd(s, i); end > 0 {
+				i = end
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+
+		last = c
+	}
+
+	return depth <= 0 && last == ';'
+}
+
+// closingQuote finds the quote that ends a literal starting at i, skipping escaped characters. It
+// returns -1 if the literal is not closed.
+func closingQuote(s string, i int, quote string) int {
+	for ; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], quote):
+			return i
+		case len(quote) == 1 && s[i] == '\n':
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// iriEnd finds the '>' that ends an IRI starting at i. It returns -1 if the '<' does not start an
+// IRI, e.g. when it is a less than operator.
+func iriEnd(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '>':
+			return j
+		case c <= ' ' || strings.IndexByte("<\"{}|^`\\", c) >= 0:
+			return -1
+		}
+	}
+
+	return -1
+}
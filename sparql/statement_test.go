@@ -0,0 +1,51 @@
+package sparql
+
+import "testing"
+
+func TestTerminated(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      bool
+	}{
+		{"query", "SELECT * WHERE { ?s ?p ?o };", true},
+		{"query without ;", "SELECT * WHERE { ?s ?p ?o }", false},
+		{"predicate list", "CONSTRUCT { ?s <p> ?o ;", false},
+		{"predicate list closed", "CONSTRUCT { ?s <p> ?o ;\n <q> ?x } WHERE { ?s <p> ?o ; <q> ?x };", true},
+		{"literal", "SELECT * WHERE { ?s ?p \"a;\n", false},
+		{"long literal", "SELECT * WHERE { ?s ?p \"\"\"a\nb;", false},
+		{"escaped quote", `SELECT * WHERE { ?s ?p "a\";" };`, true},
+		{"iri", "SELECT * WHERE { ?s <http://example.com/;", false},
+		{"less than", "SELECT * WHERE { ?s ?p ?o FILTER (?o < 3) };", true},
+		{"comment", "SELECT * WHERE { ?s ?p ?o } # done;", false},
+		{"comment after ;", "SELECT * WHERE { ?s ?p ?o }; # done", true},
+		{"update", "INSERT DATA { <a> <b> <c> };", false},
+		{"multi-operation update", "INSERT DATA { <a> <b> <c> };\nDELETE WHERE { ?s <b> ?o };", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Terminated(tt.statement); got != tt.want {
+				t.Errorf("Terminated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndsWithSemicolon(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{"multi-operation update", "INSERT DATA { <a> <b> <c> };\nDELETE WHERE { ?s <b> ?o };", true},
+		{"between operations", "INSERT DATA { <a> <b> <c> };\nDELETE WHERE { ?s <b> ?o ;", false},
+		{"literal", "INSERT DATA { <a> <b> 'x;' }", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := endsWithSemicolon(tt.s); got != tt.want {
+				t.Errorf("endsWithSemicolon() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
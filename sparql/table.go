@@ -0,0 +1,51 @@
+package sparql
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mick-roper/rdfox-cli/ttl"
+)
+
+// WriteTable renders results as an aligned, human readable table. ASK results are written as true or false.
+// IRIs are written as prefixed names where one of the prefixes matches, and prefixes may be nil.
+func WriteTable(res *Results, dst io.Writer, prefixes ttl.Prefixes) error {
+	if res.Boolean != nil {
+		_, err := fmt.Fprintln(dst, *res.Boolean)
+		return err
+	}
+
+	w := tabwriter.NewWriter(dst, 0, 4, 2, ' ', 0)
+
+	header := make([]string, len(res.Head.Vars))
+	rule := make([]string, len(res.Head.Vars))
+	for i, v := range res.Head.Vars {
+		header[i] = "?" + v
+		rule[i] = strings.Repeat("-", len(header[i]))
+	}
+
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	fmt.Fprintln(w, strings.Join(rule, "\t"))
+
+	row := make([]string, len(res.Head.Vars))
+	for _, binding := range res.Results.Bindings {
+		for i, v := range res.Head.Vars {
+			row[i] = ""
+
+			if b, ok := binding[v]; ok {
+				row[i] = b.Format(prefixes)
+			}
+		}
+
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(dst, "\n%d row(s)\n", len(res.Results.Bindings))
+	return err
+}
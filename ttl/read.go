@@ -0,0 +1,548 @@
+package ttl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Read parses a Turtle document. It supports the subset of Turtle produced by Writer - directives,
+// IRIs, prefixed names, blank node labels, literals, numbers and booleans, and predicate and object
+// lists - but not collections or anonymous blank nodes.
+func Read(src io.Reader) ([]Triple, Prefixes, error) {
+	b, err := io.ReadAll(src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := parser{src: string(b), prefixes: Prefixes{}}
+
+	triples, err := p.document()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w at offset %d", err, p.pos)
+	}
+
+	return triples, p.prefixes, nil
+}
+
+// ReadPrefixes reads the prefixes declared by the directives at the start of a Turtle document,
+// resolving them against any base. It stops at the first statement that is not a directive, so the
+// rest of the document may use Turtle that Read does not support.
+func ReadPrefixes(src io.Reader) (Prefixes, error) {
+	b, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{src: string(b), prefixes: Prefixes{}}
+
+	for {
+		p.skipSpace()
+
+		ok, err := p.directive()
+		if err != nil {
+			return nil, fmt.Errorf("%w at offset %d", err, p.pos)
+		}
+
+		if !ok {
+			return p.prefixes, nil
+		}
+	}
+}
+
+type parser struct {
+	src      string
+	pos      int
+	base     string
+	prefixes Prefixes
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *parser) rest() string {
+	return p.src[p.pos:]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expect(s string) error {
+	p.skipSpace()
+
+	if !strings.HasPrefix(p.rest(), s) {
+		return fmt.Errorf("expected %q", s)
+	}
+
+	p.pos += len(s)
+
+	return nil
+}
+
+// keyword consumes a case insensitive keyword, such as PREFIX, if it is next in the input and is
+// not the start of a longer name.
+func (p *parser) keyword(k string) bool {
+	if len(p.rest()) < len(k) || !strings.EqualFold(p.rest()[:len(k)], k) {
+		return false
+	}
+
+	if next := p.pos + len(k); next < len(p.src) {
+		if c := p.src[next]; isAlphaNum(c) || c == '_' || c == '-' || c == ':' {
+			return false
+		}
+	}
+
+	p.pos += len(k)
+
+	return true
+}
+
+func (p *parser) document() ([]Triple, error) {
+	var triples []Triple
+
+	for {
+		p.skipSpace()
+
+		if p.eof() {
+			return triples, nil
+		}
+
+		ok, err := p.directive()
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			continue
+		}
+
+		t, err := p.triples()
+		if err != nil {
+			return nil, err
+		}
+
+		triples = append(triples, t...)
+	}
+}
+
+// directive reads a prefix or base directive if one is next in the input, and reports whether it did.
+func (p *parser) directive() (bool, error) {
+	switch {
+	case p.keyword("@prefix"):
+		if err := p.prefixDirective(); err != nil {
+			return false, err
+		}
+
+		return true, p.expect(".")
+	case p.keyword("@base"):
+		if err := p.baseDirective(); err != nil {
+			return false, err
+		}
+
+		return true, p.expect(".")
+	case p.keyword("PREFIX"):
+		return true, p.prefixDirective()
+	case p.keyword("BASE"):
+		return true, p.baseDirective()
+	default:
+		return false, nil
+	}
+}
+
+func (p *parser) prefixDirective() error {
+	p.skipSpace()
+
+	i := strings.IndexByte(p.rest(), ':')
+	if i < 0 {
+		return errors.New("expected a prefix name")
+	}
+
+	name := p.rest()[:i]
+	p.pos += i + 1
+
+	p.skipSpace()
+
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+
+	p.prefixes[name] = string(iri)
+
+	return nil
+}
+
+func (p *parser) baseDirective() error {
+	p.skipSpace()
+
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+
+	p.base = string(iri)
+
+	return nil
+}
+
+func (p *parser) triples() ([]Triple, error) {
+	subject, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	var triples []Triple
+
+	for {
+		p.skipSpace()
+
+		var predicate Term
+
+		if p.keyword("a") {
+			predicate = IRI(RDFType)
+		} else if predicate, err = p.term(); err != nil {
+			return nil, err
+		}
+
+		for {
+			object, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+
+			triples = append(triples, Triple{subject, predicate, object})
+
+			p.skipSpace()
+
+			if p.peek() != ',' {
+				break
+			}
+
+			p.pos++
+		}
+
+		p.skipSpace()
+
+		switch p.peek() {
+		case ';':
+			p.pos++
+			p.skipSpace()
+
+			// a trailing ';' before the '.' is allowed
+			if p.peek() == '.' {
+				p.pos++
+				return triples, nil
+			}
+		case '.':
+			p.pos++
+			return triples, nil
+		default:
+			return nil, errors.New("expected ';', ',' or '.'")
+		}
+	}
+}
+
+func (p *parser) term() (Term, error) {
+	p.skipSpace()
+
+	switch c := p.peek(); {
+	case p.eof():
+		return nil, errors.New("unexpected end of input")
+	case c == '<':
+		return p.iriRef()
+	case c == '"' || c == '\'':
+		return p.literal()
+	case strings.HasPrefix(p.rest(), "_:"):
+		p.pos += 2
+		return BlankNode(p.name()), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	case p.keyword("true"):
+		return Literal{Value: "true", Datatype: XSDBoolean}, nil
+	case p.keyword("false"):
+		return Literal{Value: "false", Datatype: XSDBoolean}, nil
+	default:
+		return p.prefixedName()
+	}
+}
+
+func (p *parser) iriRef() (IRI, error) {
+	if err := p.expect("<"); err != nil {
+		return "", err
+	}
+
+	end := strings.IndexByte(p.rest(), '>')
+	if end < 0 {
+		return "", errors.New("unterminated IRI")
+	}
+
+	raw := p.rest()[:end]
+	p.pos += end + 1
+
+	s, err := unescape(raw)
+	if err != nil {
+		return "", err
+	}
+
+	if p.base != "" && !schemePattern.MatchString(s) {
+		s = resolve(p.base, s)
+	}
+
+	return IRI(s), nil
+}
+
+var schemePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.\-]*:`)
+
+// resolve resolves a relative IRI against base. Unlike url.ResolveReference it never re-encodes
+// characters or removes an empty fragment, so IRIs come back exactly as they were written.
+func resolve(base, ref string) string {
+	switch {
+	case ref == "":
+		return base
+	case strings.HasPrefix(ref, "#"):
+		if i := strings.IndexByte(base, '#'); i >= 0 {
+			base = base[:i]
+		}
+
+		return base + ref
+	case strings.HasPrefix(ref, "//"):
+		return base[:strings.Index(base, ":")+1] + ref
+	case strings.HasPrefix(ref, "/"):
+		if i := strings.Index(base, "://"); i >= 0 {
+			if j := strings.IndexByte(base[i+3:], '/'); j >= 0 {
+				return base[:i+3+j] + ref
+			}
+		}
+
+		return strings.TrimSuffix(base, "/") + ref
+	default:
+		if i := strings.IndexAny(base, "?#"); i >= 0 {
+			base = base[:i]
+		}
+
+		return base[:strings.LastIndexByte(base, '/')+1] + ref
+	}
+}
+
+// name reads a blank node label or the local part of a prefixed name.
+func (p *parser) name() string {
+	var sb strings.Builder
+
+	for !p.eof() {
+		c := p.peek()
+
+		if c == '\\' && p.pos+1 < len(p.src) {
+			sb.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+			continue
+		}
+
+		if c <= ' ' || strings.IndexByte(`<>"'(){}[];,#`, c) >= 0 {
+			break
+		}
+
+		// a '.' can only appear inside a name, not at the end where it ends the statement
+		if c == '.' && (p.pos+1 >= len(p.src) || strings.IndexByte(" \t\r\n#", p.src[p.pos+1]) >= 0) {
+			break
+		}
+
+		sb.WriteByte(c)
+		p.pos++
+	}
+
+	return sb.String()
+}
+
+func (p *parser) prefixedName() (Term, error) {
+	i := strings.IndexByte(p.rest(), ':')
+	if i < 0 || strings.ContainsAny(p.rest()[:i], " \t\r\n") {
+		return nil, fmt.Errorf("unexpected input: %.20q", p.rest())
+	}
+
+	prefix := p.rest()[:i]
+
+	ns, ok := p.prefixes[prefix]
+	if !ok {
+		return nil, fmt.Errorf("undefined prefix: %s", prefix)
+	}
+
+	p.pos += i + 1
+
+	return IRI(ns + p.name()), nil
+}
+
+func (p *parser) literal() (Term, error) {
+	quote := p.rest()[:1]
+	if long := strings.Repeat(quote, 3); strings.HasPrefix(p.rest(), long) {
+		quote = long
+	}
+
+	p.pos += len(quote)
+
+	end := -1
+	for i := p.pos; i < len(p.src); i++ {
+		if p.src[i] == '\\' {
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(p.src[i:], quote) {
+			end = i
+			break
+		}
+	}
+
+	if end < 0 {
+		return nil, errors.New("unterminated literal")
+	}
+
+	value, err := unescape(p.src[p.pos:end])
+	if err != nil {
+		return nil, err
+	}
+
+	p.pos = end + len(quote)
+
+	l := Literal{Value: value}
+
+	switch {
+	case p.peek() == '@':
+		p.pos++
+		start := p.pos
+		for !p.eof() && (isAlphaNum(p.peek()) || p.peek() == '-') {
+			p.pos++
+		}
+
+		l.Lang = p.src[start:p.pos]
+	case strings.HasPrefix(p.rest(), "^^"):
+		p.pos += 2
+
+		dt, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+
+		iri, ok := dt.(IRI)
+		if !ok {
+			return nil, errors.New("datatype must be an IRI")
+		}
+
+		if string(iri) != XSDString {
+			l.Datatype = string(iri)
+		}
+	}
+
+	return l, nil
+}
+
+func (p *parser) number() (Term, error) {
+	start := p.pos
+
+	for !p.eof() && strings.IndexByte("0123456789+-.eE", p.peek()) >= 0 {
+		p.pos++
+	}
+
+	// '1.' is the integer 1 followed by the end of the statement
+	for p.pos > start && p.src[p.pos-1] == '.' {
+		p.pos--
+	}
+
+	s := p.src[start:p.pos]
+
+	switch {
+	case integerPattern.MatchString(s):
+		return Literal{Value: s, Datatype: XSDInteger}, nil
+	case decimalPattern.MatchString(s):
+		return Literal{Value: s, Datatype: XSDDecimal}, nil
+	case doublePattern.MatchString(s):
+		return Literal{Value: s, Datatype: XSDDouble}, nil
+	default:
+		return nil, fmt.Errorf("invalid number: %s", s)
+	}
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		if i+1 >= len(s) {
+			return "", errors.New("invalid escape sequence")
+		}
+
+		i++
+
+		switch c := s[i]; c {
+		case 't':
+			sb.WriteByte('\t')
+		case 'b':
+			sb.WriteByte('\b')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case '"', '\'', '\\':
+			sb.WriteByte(c)
+		case 'u', 'U':
+			n := 4
+			if c == 'U' {
+				n = 8
+			}
+
+			if i+n >= len(s) {
+				return "", errors.New("invalid unicode escape")
+			}
+
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", fmt.Errorf("invalid unicode escape: %s", s[i-1:i+1+n])
+			}
+
+			sb.WriteRune(rune(r))
+			i += n
+		default:
+			return "", fmt.Errorf("invalid escape sequence: \\%c", c)
+		}
+	}
+
+	return sb.String(), nil
+}
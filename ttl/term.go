@@ -0,0 +1,207 @@
+package ttl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// well known datatypes
+const (
+	XSDString     = "http://www.w3.org/2001/XMLSchema#string"
+	XSDBoolean    = "http://www.w3.org/2001/XMLSchema#boolean"
+	XSDInteger    = "http://www.w3.org/2001/XMLSchema#integer"
+	XSDDecimal    = "http://www.w3.org/2001/XMLSchema#decimal"
+	XSDDouble     = "http://www.w3.org/2001/XMLSchema#double"
+	RDFLangString = "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"
+	RDFType       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+)
+
+// Term is an RDF term: an IRI, a blank node or a literal.
+type Term interface {
+	// String returns the term in its full N-Triples form.
+	String() string
+	Validate() error
+}
+
+type IRI string
+
+type BlankNode string
+
+type Literal struct {
+	Value string
+
+	// Datatype is empty for plain xsd:string and language tagged literals.
+	Datatype string
+	Lang     string
+}
+
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+func (t Triple) Validate() error {
+	if t.Subject == nil || t.Predicate == nil || t.Object == nil {
+		return errors.New("triple has a missing term")
+	}
+
+	if _, ok := t.Subject.(Literal); ok {
+		return fmt.Errorf("literal %s cannot be a subject", t.Subject)
+	}
+
+	if _, ok := t.Predicate.(IRI); !ok {
+		return fmt.Errorf("%s cannot be a predicate", t.Predicate)
+	}
+
+	for _, term := range []Term{t.Subject, t.Predicate, t.Object} {
+		if err := term.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i IRI) String() string {
+	return "<" + escapeIRI(string(i)) + ">"
+}
+
+func (i IRI) Validate() error {
+	if i == "" {
+		return errors.New("IRI is empty")
+	}
+
+	if !strings.Contains(string(i), ":") {
+		return fmt.Errorf("IRI <%s> is not absolute", string(i))
+	}
+
+	if !utf8.ValidString(string(i)) {
+		return fmt.Errorf("IRI <%s> is not valid UTF-8", string(i))
+	}
+
+	return nil
+}
+
+var blankNodeLabel = regexp.MustCompile(`^[A-Za-z0-9_\p{L}]([A-Za-z0-9_\-.\p{L}]*[A-Za-z0-9_\-\p{L}])?$`)
+
+func (b BlankNode) String() string {
+	return "_:" + string(b)
+}
+
+func (b BlankNode) Validate() error {
+	if !blankNodeLabel.MatchString(string(b)) {
+		return fmt.Errorf("invalid blank node label: %s", string(b))
+	}
+
+	return nil
+}
+
+var langTag = regexp.MustCompile(`^[a-zA-Z]+(-[a-zA-Z0-9]+)*$`)
+
+func (l Literal) String() string {
+	s := `"` + escapeString(l.Value) + `"`
+
+	if l.Lang != "" {
+		return s + "@" + l.Lang
+	}
+
+	if l.Datatype != "" && l.Datatype != XSDString {
+		return s + "^^" + IRI(l.Datatype).String()
+	}
+
+	return s
+}
+
+func (l Literal) Validate() error {
+	if !utf8.ValidString(l.Value) {
+		return errors.New("literal is not valid UTF-8")
+	}
+
+	if l.Lang != "" {
+		if !langTag.MatchString(l.Lang) {
+			return fmt.Errorf("invalid language tag: %s", l.Lang)
+		}
+
+		if l.Datatype != "" && l.Datatype != RDFLangString {
+			return errors.New("a literal cannot have both a language tag and a datatype")
+		}
+
+		return nil
+	}
+
+	if l.Datatype != "" {
+		return IRI(l.Datatype).Validate()
+	}
+
+	return nil
+}
+
+// ParseTerm parses a single term written in N-Triples or Turtle syntax, as returned in the cells of
+// a SPARQL TSV result. Bare numbers and booleans are turned into typed literals.
+func ParseTerm(s string) (Term, error) {
+	s = strings.TrimSpace(s)
+
+	p := parser{src: s}
+
+	t, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(s) {
+		return nil, fmt.Errorf("unexpected content after term: %q", s[p.pos:])
+	}
+
+	return t, nil
+}
+
+func escapeString(s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+				continue
+			}
+
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func escapeIRI(s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		if r <= 0x20 || strings.ContainsRune(`<>"{}|^`+"`"+`\`, r) {
+			fmt.Fprintf(&sb, `\u%04X`, r)
+			continue
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -1,48 +1,253 @@
 package ttl
 
 import (
-	"bytes"
+	"bufio"
 	"io"
+	"regexp"
+	"sort"
+	"strings"
 )
 
-type triples map[string]map[string][]string
+// Prefixes maps prefix names (without the colon) to namespace IRIs.
+type Prefixes map[string]string
 
-func Write(data triples, dst io.Writer) error {
-	var buffer bytes.Buffer
-	for s, duples := range data {
-		buffer.WriteString(s)
+var (
+	prefixNamePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_\-]*)?$`)
+	localNamePattern  = regexp.MustCompile(`^([A-Za-z0-9_]([A-Za-z0-9_\-.]*[A-Za-z0-9_\-])?)?$`)
 
-		var i int
-		var dCount int
+	integerPattern = regexp.MustCompile(`^[+-]?[0-9]+$`)
+	decimalPattern = regexp.MustCompile(`^[+-]?[0-9]*\.[0-9]+$`)
+	doublePattern  = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)[eE][+-]?[0-9]+$`)
+)
+
+// Compact writes iri as a prefixed name using the longest matching namespace. It reports false if no
+// namespace matches or the remainder is not a valid local name.
+func (p Prefixes) Compact(iri string) (string, bool) {
+	best, bestNS := "", ""
+	found := false
 
-		for _, objects := range duples {
-			dCount += len(objects)
+	for name, ns := range p {
+		if ns == "" || !strings.HasPrefix(iri, ns) || !prefixNamePattern.MatchString(name) {
+			continue
 		}
 
-		for p, objects := range duples {
-			for _, o := range objects {
-				i++
-				buffer.WriteString("\n\t")
-				buffer.WriteString(p)
-				buffer.WriteRune('\t')
-				buffer.WriteString(o)
-				buffer.WriteRune('\t')
+		if !localNamePattern.MatchString(iri[len(ns):]) {
+			continue
+		}
 
-				if i < dCount {
-					buffer.WriteRune(';')
-				}
-			}
+		// prefer the longest namespace, then the alphabetically first name so output is stable
+		if !found || len(ns) > len(bestNS) || (len(ns) == len(bestNS) && name < best) {
+			best, bestNS, found = name, ns, true
+		}
+	}
 
+	if !found {
+		return "", false
+	}
+
+	return best + ":" + iri[len(bestNS):], true
+}
+
+// Writer writes triples as Turtle. The @base and @prefix directives are written before the first
+// batch of triples, and each batch is sorted and grouped by subject and predicate so the output is
+// deterministic.
+type Writer struct {
+	dst         io.Writer
+	base        string
+	prefixes    Prefixes
+	wroteHeader bool
+}
+
+// NewWriter creates a Writer. base and prefixes may be empty, in which case every IRI is written in full.
+func NewWriter(dst io.Writer, base string, prefixes Prefixes) *Writer {
+	return &Writer{dst: dst, base: base, prefixes: prefixes}
+}
+
+// Write writes a single batch of triples as a complete Turtle document without any prefixes.
+func Write(triples []Triple, dst io.Writer) error {
+	return NewWriter(dst, "", nil).Write(triples)
+}
+
+// WriteHeader writes the @base and @prefix directives. It is called by the first Write, and only
+// needs to be called directly to produce a document with no triples.
+func (w *Writer) WriteHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+
+	w.wroteHeader = true
+
+	var sb strings.Builder
+
+	if w.base != "" {
+		sb.WriteString("@base " + IRI(w.base).String() + " .\n")
+	}
+
+	names := make([]string, 0, len(w.prefixes))
+	for name := range w.prefixes {
+		if prefixNamePattern.MatchString(name) {
+			names = append(names, name)
 		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString("@prefix " + name + ": " + IRI(w.prefixes[name]).String() + " .\n")
+	}
+
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w.dst, sb.String())
+
+	return err
+}
 
-		buffer.WriteString(".\n")
+// SkipHeader stops the Writer from writing the @base and @prefix directives, for appending triples to
+// a document that already has them.
+func (w *Writer) SkipHeader() {
+	w.wroteHeader = true
+}
 
-		if _, err := dst.Write(buffer.Bytes()); err != nil {
+func (w *Writer) Write(triples []Triple) error {
+	for _, t := range triples {
+		if err := t.Validate(); err != nil {
 			return err
 		}
+	}
+
+	if err := w.WriteHeader(); err != nil {
+		return err
+	}
+
+	sorted := make([]Triple, len(triples))
+	copy(sorted, triples)
+	sortTriples(sorted)
+
+	buffer := bufio.NewWriter(w.dst)
+
+	for i, t := range sorted {
+		switch {
+		case i > 0 && same(t, sorted[i-1]):
+			continue
+		case i == 0 || t.Subject != sorted[i-1].Subject:
+			if i > 0 {
+				buffer.WriteString(" .\n")
+			}
+
+			buffer.WriteString(w.format(t.Subject))
+			buffer.WriteString(" ")
+			buffer.WriteString(w.formatPredicate(t.Predicate))
+		case t.Predicate != sorted[i-1].Predicate:
+			buffer.WriteString(" ;\n    ")
+			buffer.WriteString(w.formatPredicate(t.Predicate))
+		default:
+			buffer.WriteString(" ,\n        ")
+			buffer.WriteString(w.format(t.Object))
+			continue
+		}
 
-		buffer.Reset()
+		buffer.WriteString(" ")
+		buffer.WriteString(w.format(t.Object))
 	}
 
-	return nil
+	if len(sorted) > 0 {
+		buffer.WriteString(" .\n")
+	}
+
+	return buffer.Flush()
+}
+
+func (w *Writer) formatPredicate(t Term) string {
+	if t == IRI(RDFType) {
+		return "a"
+	}
+
+	return w.format(t)
+}
+
+func (w *Writer) format(t Term) string {
+	switch v := t.(type) {
+	case IRI:
+		return w.formatIRI(string(v))
+	case Literal:
+		return w.formatLiteral(v)
+	default:
+		return t.String()
+	}
+}
+
+func (w *Writer) formatIRI(iri string) string {
+	if s, ok := w.prefixes.Compact(iri); ok {
+		return s
+	}
+
+	if rel, ok := relativeTo(w.base, iri); ok {
+		return IRI(rel).String()
+	}
+
+	return IRI(iri).String()
+}
+
+func (w *Writer) formatLiteral(l Literal) string {
+	switch {
+	case l.Datatype == XSDInteger && integerPattern.MatchString(l.Value),
+		l.Datatype == XSDDecimal && decimalPattern.MatchString(l.Value),
+		l.Datatype == XSDDouble && doublePattern.MatchString(l.Value),
+		l.Datatype == XSDBoolean && (l.Value == "true" || l.Value == "false"):
+		return l.Value
+	}
+
+	if l.Lang != "" || l.Datatype == "" || l.Datatype == XSDString {
+		return l.String()
+	}
+
+	return `"` + escapeString(l.Value) + `"^^` + w.formatIRI(l.Datatype)
+}
+
+// relativeTo makes iri relative to a base ending in '/', when the result resolves back to iri.
+func relativeTo(base, iri string) (string, bool) {
+	if base == "" || !strings.HasSuffix(base, "/") || !strings.HasPrefix(iri, base) {
+		return "", false
+	}
+
+	rel := iri[len(base):]
+
+	if rel == "" || strings.HasPrefix(rel, "/") || strings.HasPrefix(rel, ".") || strings.Contains(rel, "/.") {
+		return "", false
+	}
+
+	// a colon before the first '/' would be read as a scheme
+	if i := strings.IndexAny(rel, ":/?#"); i >= 0 && rel[i] == ':' {
+		return "", false
+	}
+
+	return rel, true
+}
+
+func same(a, b Triple) bool {
+	return a.Subject == b.Subject && a.Predicate == b.Predicate && a.Object == b.Object
+}
+
+func sortTriples(triples []Triple) {
+	sort.SliceStable(triples, func(i, j int) bool {
+		a, b := triples[i], triples[j]
+
+		if s1, s2 := a.Subject.String(), b.Subject.String(); s1 != s2 {
+			return s1 < s2
+		}
+
+		// rdf:type first, as readers expect
+		if a.Predicate != b.Predicate {
+			if a.Predicate == IRI(RDFType) || b.Predicate == IRI(RDFType) {
+				return a.Predicate == IRI(RDFType)
+			}
+
+			return a.Predicate.String() < b.Predicate.String()
+		}
+
+		return a.Object.String() < b.Object.String()
+	})
 }
@@ -2,12 +2,19 @@ package ttl
 
 import (
 	"bytes"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
+const ex = "http://example.com/"
+
 func TestWrite(t *testing.T) {
 	type args struct {
-		data triples
+		base     string
+		prefixes Prefixes
+		triples  []Triple
 	}
 	tests := []struct {
 		name    string
@@ -18,30 +25,231 @@ func TestWrite(t *testing.T) {
 		{
 			name: "happy path",
 			args: args{
-				data: triples{
-					"abc": map[string][]string{
-						"xyz": {
-							"one",
-							"two",
-							"three",
-						},
-					},
+				triples: []Triple{
+					{IRI(ex + "abc"), IRI(ex + "xyz"), Literal{Value: "two"}},
+					{IRI(ex + "abc"), IRI(ex + "xyz"), Literal{Value: "one"}},
+					{IRI(ex + "abc"), IRI(ex + "xyz"), Literal{Value: "three"}},
+				},
+			},
+			wantDst: `<http://example.com/abc> <http://example.com/xyz> "one" ,
+        "three" ,
+        "two" .
+`,
+		},
+		{
+			name: "prefixes, grouping and sorting",
+			args: args{
+				prefixes: Prefixes{"ex": ex, "xsd": "http://www.w3.org/2001/XMLSchema#"},
+				triples: []Triple{
+					{IRI(ex + "b"), IRI(ex + "name"), Literal{Value: "b", Lang: "en"}},
+					{IRI(ex + "a"), IRI(ex + "size"), Literal{Value: "42", Datatype: XSDInteger}},
+					{IRI(ex + "a"), IRI(RDFType), IRI(ex + "Thing")},
+					{IRI(ex + "a"), IRI(ex + "when"), Literal{Value: "2023-01-01", Datatype: "http://www.w3.org/2001/XMLSchema#date"}},
+					{IRI(ex + "a"), IRI(ex + "size"), Literal{Value: "42", Datatype: XSDInteger}},
+					{BlankNode("b0"), IRI(ex + "other"), IRI("http://other.com/a b")},
 				},
 			},
-			wantDst: "abc\n\txyz\tone\t;\n\txyz\ttwo\t;\n\txyz\tthree\t.\n",
-			wantErr: false,
+			wantDst: `@prefix ex: <http://example.com/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+ex:a a ex:Thing ;
+    ex:size 42 ;
+    ex:when "2023-01-01"^^xsd:date .
+ex:b ex:name "b"@en .
+_:b0 ex:other <http://other.com/a\u0020b> .
+`,
+		},
+		{
+			name: "base and escaping",
+			args: args{
+				base: ex,
+				triples: []Triple{
+					{IRI(ex + "a"), IRI(ex + "says"), Literal{Value: "line one\nsaid \"hi\"\t\\"}},
+				},
+			},
+			wantDst: `@base <http://example.com/> .
+
+<a> <says> "line one\nsaid \"hi\"\t\\" .
+`,
+		},
+		{
+			name: "literal subject is invalid",
+			args: args{
+				triples: []Triple{{Literal{Value: "x"}, IRI(ex + "p"), IRI(ex + "o")}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid language tag",
+			args: args{
+				triples: []Triple{{IRI(ex + "s"), IRI(ex + "p"), Literal{Value: "x", Lang: "en gb"}}},
+			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dst := &bytes.Buffer{}
-			if err := Write(tt.args.data, dst); (err != nil) != tt.wantErr {
+			if err := NewWriter(dst, tt.args.base, tt.args.prefixes).Write(tt.args.triples); (err != nil) != tt.wantErr {
 				t.Errorf("Write() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if gotDst := dst.String(); gotDst != tt.wantDst {
+			if gotDst := dst.String(); !tt.wantErr && gotDst != tt.wantDst {
 				t.Errorf("Write() = %v, want %v", gotDst, tt.wantDst)
 			}
 		})
 	}
 }
+
+func TestWriteIsDeterministic(t *testing.T) {
+	triples := []Triple{
+		{IRI(ex + "c"), IRI(ex + "p"), IRI(ex + "o")},
+		{IRI(ex + "a"), IRI(ex + "q"), Literal{Value: "1"}},
+		{IRI(ex + "a"), IRI(ex + "p"), Literal{Value: "2"}},
+		{IRI(ex + "b"), IRI(ex + "p"), BlankNode("x")},
+	}
+
+	var first bytes.Buffer
+	if err := Write(triples, &first); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		// rotate the input so every run sees a different order
+		triples = append(triples[1:], triples[0])
+
+		var next bytes.Buffer
+		if err := Write(triples, &next); err != nil {
+			t.Fatal(err)
+		}
+
+		if next.String() != first.String() {
+			t.Fatalf("Write() output changed:\n%s\nvs\n%s", first.String(), next.String())
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	triples := []Triple{
+		{IRI(ex + "a"), IRI(RDFType), IRI(ex + "Thing")},
+		{IRI(ex + "a"), IRI(ex + "label"), Literal{Value: "plain"}},
+		{IRI(ex + "a"), IRI(ex + "label"), Literal{Value: "tagged", Lang: "en-GB"}},
+		{IRI(ex + "a"), IRI(ex + "label"), Literal{Value: "quotes \" and 'apostrophes' and \\ slashes"}},
+		{IRI(ex + "a"), IRI(ex + "label"), Literal{Value: "multi\nline\r\nwith\ttabs and \u0001 control"}},
+		{IRI(ex + "a"), IRI(ex + "label"), Literal{Value: "unicode ✓ ünïcödé"}},
+		{IRI(ex + "a"), IRI(ex + "count"), Literal{Value: "-12", Datatype: XSDInteger}},
+		{IRI(ex + "a"), IRI(ex + "count"), Literal{Value: "3.14", Datatype: XSDDecimal}},
+		{IRI(ex + "a"), IRI(ex + "count"), Literal{Value: "1.5E10", Datatype: XSDDouble}},
+		{IRI(ex + "a"), IRI(ex + "count"), Literal{Value: "not a number", Datatype: XSDInteger}},
+		{IRI(ex + "a"), IRI(ex + "flag"), Literal{Value: "true", Datatype: XSDBoolean}},
+		{IRI(ex + "a"), IRI(ex + "local.name-with_chars"), IRI(ex + "x.")},
+		{IRI(ex + "path/with/slashes"), IRI(ex + "p"), IRI("urn:uuid:1234")},
+		{IRI(ex + "a"), IRI(ex + "p"), IRI("http://other.com/{odd}|<chars>")},
+		{BlankNode("node1"), IRI(ex + "p"), BlankNode("node2")},
+	}
+
+	for _, base := range []string{"", ex} {
+		for _, prefixes := range []Prefixes{nil, {"ex": ex, "": ex + "path/", "xsd": "http://www.w3.org/2001/XMLSchema#"}} {
+			var buffer bytes.Buffer
+
+			if err := NewWriter(&buffer, base, prefixes).Write(triples); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			got, _, err := Read(&buffer)
+			if err != nil {
+				t.Fatalf("Read() error = %v\n%s", err, buffer.String())
+			}
+
+			if want := sorted(triples); !reflect.DeepEqual(sorted(got), want) {
+				t.Errorf("round trip with base %q and prefixes %v:\ngot  %v\nwant %v", base, prefixes, sorted(got), want)
+			}
+		}
+	}
+}
+
+func TestParseTerm(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Term
+		wantErr bool
+	}{
+		{in: "<http://example.com/a>", want: IRI(ex + "a")},
+		{in: "_:b1", want: BlankNode("b1")},
+		{in: `"hello"`, want: Literal{Value: "hello"}},
+		{in: `"hello"@en`, want: Literal{Value: "hello", Lang: "en"}},
+		{in: `"1"^^<http://www.w3.org/2001/XMLSchema#integer>`, want: Literal{Value: "1", Datatype: XSDInteger}},
+		{in: `"x"^^<http://www.w3.org/2001/XMLSchema#string>`, want: Literal{Value: "x"}},
+		{in: `"tab\there \u00e9"`, want: Literal{Value: "tab\there é"}},
+		{in: "42", want: Literal{Value: "42", Datatype: XSDInteger}},
+		{in: "-0.5", want: Literal{Value: "-0.5", Datatype: XSDDecimal}},
+		{in: "false", want: Literal{Value: "false", Datatype: XSDBoolean}},
+		{in: `"unterminated`, wantErr: true},
+		{in: "ex:a", wantErr: true},
+		{in: "<a> <b>", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTerm(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTerm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseTerm() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPrefixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    Prefixes
+		wantErr bool
+	}{
+		{
+			name: "turtle with a body",
+			src: `# a document Read does not support
+@base <http://example.com/> .
+@prefix ex: <vocab#> .
+PREFIX foaf: <http://xmlns.com/foaf/0.1/>
+
+ex:alice a foaf:Person ;
+    foaf:knows [ foaf:name "Bob" ] ;
+    ex:likes ( ex:tea ex:cake ) ;
+    ex:bio """multi
+line; with a PREFIX in it""" .
+
+@prefix late: <http://example.com/late#> .
+`,
+			want: Prefixes{"ex": "http://example.com/vocab#", "foaf": "http://xmlns.com/foaf/0.1/"},
+		},
+		{name: "no directives", src: "<a> <b> <c> .", want: Prefixes{}},
+		{name: "unterminated directive", src: "@prefix ex: <http://example.com/>", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadPrefixes(strings.NewReader(tt.src))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadPrefixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadPrefixes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sorted(triples []Triple) []string {
+	out := make([]string, len(triples))
+	for i, t := range triples {
+		out[i] = strings.Join([]string{t.Subject.String(), t.Predicate.String(), t.Object.String()}, " ")
+	}
+
+	sort.Strings(out)
+
+	return out
+}
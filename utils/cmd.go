@@ -1,12 +1,19 @@
 package utils
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
 
 type rootFlags struct {
 	Server   string
 	Protocol string
 	Role     string
 	Password string
+
+	PasswordCommand string
+	PasswordFile    string
 }
 
 func RootCommandFlags(cmd *cobra.Command) *rootFlags {
@@ -14,5 +21,12 @@ func RootCommandFlags(cmd *cobra.Command) *rootFlags {
 	protocol := cmd.Flags().Lookup("protocol").Value.String()
 	role := cmd.Flags().Lookup("role").Value.String()
 	password := cmd.Flags().Lookup("password").Value.String()
-	return &rootFlags{server, protocol, role, password}
+	passwordCommand := cmd.Flags().Lookup("password-command").Value.String()
+	passwordFile := cmd.Flags().Lookup("password-file").Value.String()
+	return &rootFlags{server, protocol, role, password, passwordCommand, passwordFile}
+}
+
+// Endpoint is the base URL of the RDFox server described by the flags.
+func (r *rootFlags) Endpoint() string {
+	return fmt.Sprintf("%s://%s", r.Protocol, r.Server)
 }
@@ -4,7 +4,9 @@ import "context"
 
 type bag map[any]any
 
-var bagKey = struct{}{}
+type bagKeyType struct{}
+
+var bagKey = bagKeyType{}
 
 func addToContext(ctx context.Context, key, value any) context.Context {
 	thisBag, ok := ctx.Value(bagKey).(bag)
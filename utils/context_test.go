@@ -2,7 +2,10 @@ package utils
 
 import (
 	"context"
+	"net/http"
 	"testing"
+
+	"go.uber.org/zap"
 )
 
 func TestContextUtils(t *testing.T) {
@@ -19,3 +22,56 @@ func TestContextUtils(t *testing.T) {
 		t.Errorf("want = %v, got %v", value, got)
 	}
 }
+
+func TestContextUtilsKeepsLoggerAndClient(t *testing.T) {
+	logger := zap.NewExample()
+
+	ctx := AddLoggerToContext(context.TODO(), logger)
+	ctx = AddHttpClientToContext(ctx, http.DefaultClient)
+
+	if got := LoggerFromContext(ctx); got != logger {
+		t.Errorf("LoggerFromContext() = %v, want the logger that was added", got)
+	}
+}
+
+func TestInterrupts(t *testing.T) {
+	ctx := AddInterruptsToContext(context.TODO(), &Interrupts{})
+	interrupts := InterruptsFromContext(ctx)
+
+	if interrupts.Interrupt() {
+		t.Errorf("Interrupt() without a handler = true, want false")
+	}
+
+	called := 0
+	interrupts.Handle(func() { called++ })
+
+	if !interrupts.Interrupt() || called != 1 {
+		t.Errorf("Interrupt() with a handler called it %d times, want 1", called)
+	}
+
+	interrupts.Handle(nil)
+
+	if interrupts.Interrupt() {
+		t.Errorf("Interrupt() after Handle(nil) = true, want false")
+	}
+
+	// commands run without Execute have no Interrupts
+	InterruptsFromContext(context.TODO()).Handle(func() {})
+}
+
+func TestDetach(t *testing.T) {
+	logger := zap.NewExample()
+
+	ctx, cancel := context.WithCancel(AddLoggerToContext(context.TODO(), logger))
+	cancel()
+
+	detached := Detach(ctx)
+
+	if detached.Err() != nil {
+		t.Errorf("Detach().Err() = %v, want nil", detached.Err())
+	}
+
+	if got := LoggerFromContext(detached); got != logger {
+		t.Errorf("LoggerFromContext(Detach()) = %v, want the logger that was added", got)
+	}
+}
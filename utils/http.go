@@ -10,7 +10,9 @@ import (
 	"go.uber.org/zap"
 )
 
-var httpClientKey = struct{}{}
+type httpClientKeyType struct{}
+
+var httpClientKey = httpClientKeyType{}
 
 type Client interface {
 	Do(*http.Request) (*http.Response, error)
@@ -34,18 +36,45 @@ func BasicAuthHeaderValue(username, password string) string {
 	return fmt.Sprint("Basic ", encoded)
 }
 
+// sensitiveHeaders are the headers whose values are replaced by RedactHeaders.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Redact hides a secret for logging, while still showing whether it is set.
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return "REDACTED"
+}
+
+// RedactHeaders returns a copy of h with the values of credential headers redacted.
+func RedactHeaders(h http.Header) http.Header {
+	h = h.Clone()
+
+	for _, name := range sensitiveHeaders {
+		if values, ok := h[name]; ok {
+			for i := range values {
+				values[i] = Redact(values[i])
+			}
+		}
+	}
+
+	return h
+}
+
 func RequestToLoggerFields(req *http.Request) []zap.Field {
 	return []zap.Field{
-		zap.Stringer("url", req.URL),
+		zap.String("url", req.URL.Redacted()),
 		zap.String("method", req.Method),
-		zap.Any("headers", req.Header),
+		zap.Any("headers", RedactHeaders(req.Header)),
 	}
 }
 
 func ResponseToLoggerFields(res *http.Response) []zap.Field {
 	return []zap.Field{
 		zap.String("status", res.Status),
-		zap.Any("headers", res.Header),
+		zap.Any("headers", RedactHeaders(res.Header)),
 	}
 }
 
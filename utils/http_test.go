@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", BasicAuthHeaderValue("admin", "secret"))
+	h.Set("Accept", "text/csv")
+
+	got := RedactHeaders(h)
+
+	if v := got.Get("Authorization"); v != "REDACTED" {
+		t.Errorf("Authorization = %q, want REDACTED", v)
+	}
+
+	if v := got.Get("Accept"); v != "text/csv" {
+		t.Errorf("Accept = %q, want text/csv", v)
+	}
+
+	if v := h.Get("Authorization"); v == "REDACTED" {
+		t.Error("RedactHeaders() modified the original headers")
+	}
+}
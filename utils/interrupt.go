@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type interruptsCtxKeyType struct{}
+
+var interruptsCtxKey = interruptsCtxKeyType{}
+
+// Interrupts routes interrupt signals. An interrupt cancels the running command, unless the command
+// has taken interrupts over with Handle - e.g. so that an interactive shell can cancel just the
+// statement it is running.
+type Interrupts struct {
+	mu      sync.Mutex
+	handler func()
+}
+
+// Handle calls handler for every interrupt until Handle is called again. A nil handler restores the
+// default, cancelling the command.
+func (i *Interrupts) Handle(handler func()) {
+	if i == nil {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.handler = handler
+}
+
+// Interrupt passes an interrupt to the handler, if there is one, and reports whether it was handled.
+func (i *Interrupts) Interrupt() bool {
+	if i == nil {
+		return false
+	}
+
+	i.mu.Lock()
+	handler := i.handler
+	i.mu.Unlock()
+
+	if handler == nil {
+		return false
+	}
+
+	handler()
+
+	return true
+}
+
+func AddInterruptsToContext(ctx context.Context, interrupts *Interrupts) context.Context {
+	return addToContext(ctx, interruptsCtxKey, interrupts)
+}
+
+// InterruptsFromContext returns the context's Interrupts, or nil if there are none. Handle can be
+// called on a nil Interrupts.
+func InterruptsFromContext(ctx context.Context) *Interrupts {
+	interrupts, _ := getFromContext(ctx, interruptsCtxKey).(*Interrupts)
+	return interrupts
+}
+
+// detachedContext keeps the values of a context, such as the logger and http client, but is never
+// cancelled.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// Detach returns a context with the values of ctx that is not cancelled with it, so that a command
+// can still clean up, e.g. roll back a transaction, after it has been interrupted.
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
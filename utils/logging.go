@@ -6,7 +6,9 @@ import (
 	"go.uber.org/zap"
 )
 
-var loggerCtxKey = struct{}{}
+type loggerCtxKeyType struct{}
+
+var loggerCtxKey = loggerCtxKeyType{}
 
 func AddLoggerToContext(ctx context.Context, logger *zap.Logger) context.Context {
 	return addToContext(ctx, loggerCtxKey, logger)
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type retrySafeKeyType struct{}
+
+var retrySafeKey = retrySafeKeyType{}
+
+// WithRetrySafe marks requests made with ctx as safe to retry even though their method is not
+// idempotent, e.g. read-only queries sent with POST.
+func WithRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey, true)
+}
+
+func isRetrySafe(ctx context.Context) bool {
+	safe, _ := ctx.Value(retrySafeKey).(bool)
+	return safe
+}
+
+// RetryClient retries requests that fail with a transient error, waiting with exponential backoff
+// and jitter between attempts. Idempotent requests, and requests marked with WithRetrySafe, are
+// retried after any transient failure. Other requests are only retried when the failure shows that
+// the server did not process them: the connection was refused, or the server answered 429 or 503.
+type RetryClient struct {
+	client   Client
+	retries  int
+	baseWait time.Duration
+	maxWait  time.Duration
+}
+
+// NewRetryClient wraps client so that each request is retried up to retries times, waiting at most
+// maxWait between attempts.
+func NewRetryClient(client Client, retries int, maxWait time.Duration) *RetryClient {
+	return &RetryClient{client: client, retries: retries, baseWait: 500 * time.Millisecond, maxWait: maxWait}
+}
+
+func (c *RetryClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	logger := LoggerFromContext(ctx)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = body
+		}
+
+		res, err := c.client.Do(req)
+
+		if attempt >= c.retries || !c.shouldRetry(req, res, err) {
+			return res, err
+		}
+
+		wait := c.backoff(attempt, res)
+
+		fields := []zap.Field{zap.Int("attempt", attempt+1), zap.Duration("wait", wait), zap.String("url", req.URL.Redacted())}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		} else {
+			fields = append(fields, zap.String("status", res.Status))
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		logger.Warn("request failed - retrying", fields...)
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *RetryClient) shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	// a body that has been read cannot be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil && req.Context().Err() != nil {
+		return false
+	}
+
+	if notProcessed(res, err) {
+		return true
+	}
+
+	if !isIdempotent(req) {
+		return false
+	}
+
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET)
+	}
+
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// notProcessed reports whether the failure shows the server never acted on the request.
+func notProcessed(res *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+
+	return res.StatusCode == http.StatusServiceUnavailable || res.StatusCode == http.StatusTooManyRequests
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return isRetrySafe(req.Context())
+	}
+}
+
+// backoff returns how long to wait before the next attempt: the server's Retry-After if it sent
+// one, otherwise an exponentially growing wait with jitter. Either way it is capped at maxWait.
+func (c *RetryClient) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+			return minDuration(wait, c.maxWait)
+		}
+	}
+
+	wait := c.baseWait << attempt
+	if wait <= 0 || wait > c.maxWait {
+		wait = c.maxWait
+	}
+
+	// wait between half and all of the backoff, so that clients retrying together spread out
+	wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+
+	return wait
+}
+
+// retryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(s string) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(s); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(s); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
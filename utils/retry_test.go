@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		safe         bool
+		statuses     []int
+		wantStatus   int
+		wantAttempts int
+	}{
+		{name: "success", method: http.MethodGet, statuses: []int{200}, wantStatus: 200, wantAttempts: 1},
+		{name: "get retried after 502", method: http.MethodGet, statuses: []int{502, 200}, wantStatus: 200, wantAttempts: 2},
+		{name: "post retried after 503", method: http.MethodPost, statuses: []int{503, 200}, wantStatus: 200, wantAttempts: 2},
+		{name: "post not retried after 502", method: http.MethodPost, statuses: []int{502, 200}, wantStatus: 502, wantAttempts: 1},
+		{name: "safe post retried after 502", method: http.MethodPost, safe: true, statuses: []int{502, 200}, wantStatus: 200, wantAttempts: 2},
+		{name: "client errors not retried", method: http.MethodGet, statuses: []int{404, 200}, wantStatus: 404, wantAttempts: 1},
+		{name: "gives up after retries", method: http.MethodGet, statuses: []int{503, 503, 503, 200}, wantStatus: 503, wantAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					if b, err := io.ReadAll(r.Body); err != nil || string(b) != "body" {
+						t.Errorf("attempt %d got body %q", attempts, b)
+					}
+				}
+
+				w.WriteHeader(tt.statuses[attempts])
+				attempts++
+			}))
+			defer server.Close()
+
+			client := NewRetryClient(server.Client(), 2, time.Second)
+			client.baseWait = time.Millisecond
+
+			ctx := context.Background()
+			if tt.safe {
+				ctx = WithRetrySafe(ctx)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, tt.method, server.URL, strings.NewReader("body"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+
+			res.Body.Close()
+
+			if res.StatusCode != tt.wantStatus || attempts != tt.wantAttempts {
+				t.Errorf("Do() status = %d after %d attempts, want %d after %d", res.StatusCode, attempts, tt.wantStatus, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if got, ok := retryAfter("3"); !ok || got != 3*time.Second {
+		t.Errorf("retryAfter(3) = %v, %v", got, ok)
+	}
+
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("retryAfter(soon) ok = true, want false")
+	}
+
+	client := NewRetryClient(http.DefaultClient, 1, 2*time.Second)
+	res := &http.Response{Header: http.Header{"Retry-After": {"120"}}}
+
+	if got := client.backoff(0, res); got != 2*time.Second {
+		t.Errorf("backoff() = %v, want Retry-After capped at 2s", got)
+	}
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSOptions describes the TLS settings for connecting to RDFox. Every field is optional.
+type TLSOptions struct {
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	MinVersion string
+	ServerName string
+	Insecure   bool
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewTLSConfig builds a tls.Config from the options. The CA bundle is trusted in addition to the
+// system certificates.
+func NewTLSConfig(o TLSOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.Insecure,
+	}
+
+	if o.MinVersion != "" {
+		v, ok := tlsVersions[o.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS version %s - use 1.0, 1.1, 1.2 or 1.3", o.MinVersion)
+		}
+
+		cfg.MinVersion = v
+	}
+
+	if o.CACert != "" {
+		pem, err := os.ReadFile(o.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", o.CACert)
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if (o.ClientCert == "") != (o.ClientKey == "") {
+		return nil, errors.New("client-cert and client-key must be set together")
+	}
+
+	if o.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	if err := os.WriteFile(caPath, caPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		options TLSOptions
+		wantErr bool
+	}{
+		{name: "untrusted server", options: TLSOptions{}, wantErr: true},
+		{name: "ca bundle", options: TLSOptions{CACert: caPath, MinVersion: "1.2"}},
+		{name: "insecure", options: TLSOptions{Insecure: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewTLSConfig(tt.options)
+			if err != nil {
+				t.Fatalf("NewTLSConfig() error = %v", err)
+			}
+
+			client := http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+
+			res, err := client.Get(server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err == nil {
+				res.Body.Close()
+			}
+		})
+	}
+}
+
+func TestNewTLSConfigRejectsBadOptions(t *testing.T) {
+	for _, o := range []TLSOptions{
+		{MinVersion: "1.4"},
+		{ClientCert: "cert.pem"},
+		{CACert: filepath.Join(t.TempDir(), "missing.pem")},
+	} {
+		if _, err := NewTLSConfig(o); err == nil {
+			t.Errorf("NewTLSConfig(%+v) error = nil, want error", o)
+		}
+	}
+}